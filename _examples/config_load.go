@@ -132,7 +132,7 @@ func main() {
 	_ = os.Setenv("SERVER_PORT", "3000")
 
 	// Create composite config instance
-	compositeConfig := config.NewCompositeConfig()
+	compositeConfig := config.NewCompositeConfig(nil)
 
 	// Create your application config struct
 	appConfig := &AppConfig{}
@@ -0,0 +1,215 @@
+// Command envexample generates a .env.example file from the `env`, `default`, `desc` and
+// `validate` tags of a config struct type. It builds and runs a small program calling
+// config.Describe and config.WriteEnvExample on the type, so the example follows the library's
+// rules (`envPrefix` tags, indexed slices of structs, the json option) and includes the nested
+// structs declared in other packages, e.g. config.TLS. The package declaring the type must not be
+// a main package, and its module must depend on github.com/golibry/go-config.
+//
+// Usage:
+//
+//	envexample -type AppConfig [-dir ./internal/config] [-sensitive pass,secret] [-o .env.example]
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// describeProgram is the source of the program writing the example of a type, formatted with the
+// import path of its package and its name. It reads the sensitive keywords from its argument.
+const describeProgram = `package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golibry/go-config/config"
+	target %q
+)
+
+func main() {
+	specs := config.Describe((*target.%s)(nil))
+	if err := config.WriteEnvExample(os.Stdout, specs, strings.Split(os.Args[1], ",")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`
+
+// goPackage is the part of the `go list -json` output describing a package used by envexample.
+type goPackage struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	GoFiles    []string
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "envexample:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the flags, generates the example of the config type and writes it.
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("envexample", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "directory of the Go package declaring the config type")
+	typeName := flags.String("type", "", "name of the config struct type (required)")
+	sensitive := flags.String(
+		"sensitive",
+		"pass,secret,key,token,dsn",
+		"comma-separated keywords matching the sensitive fields, whose values are left blank",
+	)
+	output := flags.String("o", "", "output file (defaults to stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *typeName == "" {
+		return errors.New("the -type flag is required")
+	}
+	if !token.IsIdentifier(*typeName) {
+		return fmt.Errorf("invalid type name %q", *typeName)
+	}
+
+	pkg, err := listPackage(*dir)
+	if err != nil {
+		return err
+	}
+	if err := checkTypeDeclared(pkg, *typeName); err != nil {
+		return err
+	}
+
+	var example bytes.Buffer
+	if err := writeExample(&example, pkg, *typeName, *sensitive); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(example.Bytes())
+		return err
+	}
+
+	return os.WriteFile(*output, example.Bytes(), 0644)
+}
+
+// listPackage returns the Go package of a directory, which must not be a main package.
+func listPackage(dir string) (goPackage, error) {
+	command := exec.Command("go", "list", "-json", ".")
+	command.Dir = dir
+	var stderr bytes.Buffer
+	command.Stderr = &stderr
+	output, err := command.Output()
+	if err != nil {
+		return goPackage{}, fmt.Errorf(
+			"failed to list the package in %s: %w: %s",
+			dir,
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	var pkg goPackage
+	if err := json.Unmarshal(output, &pkg); err != nil {
+		return goPackage{}, fmt.Errorf("failed to list the package in %s: %w", dir, err)
+	}
+	if pkg.Name == "main" {
+		return goPackage{}, fmt.Errorf(
+			"the package in %s is a main package, which cannot be imported",
+			dir,
+		)
+	}
+
+	return pkg, nil
+}
+
+// checkTypeDeclared returns an error when the package does not declare the type, so a typo is
+// reported clearly rather than as a compilation error of the generated program.
+func checkTypeDeclared(pkg goPackage, typeName string) error {
+	fileSet := token.NewFileSet()
+	for _, fileName := range pkg.GoFiles {
+		file, err := parser.ParseFile(
+			fileSet,
+			filepath.Join(pkg.Dir, fileName),
+			nil,
+			parser.SkipObjectResolution,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				if spec.(*ast.TypeSpec).Name.Name == typeName {
+					return nil
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("struct type %s not found in %s", typeName, pkg.ImportPath)
+}
+
+// writeExample runs the describe program of a type and writes its output. The program is overlaid
+// in a directory of the package, without being written to it, so it is built with the
+// dependencies of the package's module.
+func writeExample(w io.Writer, pkg goPackage, typeName string, sensitive string) error {
+	tempDir, err := os.MkdirTemp("", "envexample")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	program := filepath.Join(tempDir, "main.go")
+	source := fmt.Sprintf(describeProgram, pkg.ImportPath, typeName)
+	if err := os.WriteFile(program, []byte(source), 0644); err != nil {
+		return err
+	}
+
+	overlaidProgram := filepath.Join(pkg.Dir, ".envexample", "main.go")
+	overlay, err := json.Marshal(map[string]any{
+		"Replace": map[string]string{overlaidProgram: program},
+	})
+	if err != nil {
+		return err
+	}
+	overlayFile := filepath.Join(tempDir, "overlay.json")
+	if err := os.WriteFile(overlayFile, overlay, 0644); err != nil {
+		return err
+	}
+
+	command := exec.Command("go", "run", "-overlay", overlayFile, overlaidProgram, sensitive)
+	command.Dir = pkg.Dir
+	var stderr bytes.Buffer
+	command.Stdout = w
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return fmt.Errorf(
+			"failed to describe %s: %w: %s",
+			typeName,
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvExampleCommandTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (suite *EnvExampleCommandTestSuite) SetupTest() {
+	suite.dir = filepath.Join("testdata", "app")
+}
+
+func (suite *EnvExampleCommandTestSuite) TestItGeneratesAnEnvExampleFromAConfigType() {
+	var output bytes.Buffer
+
+	err := run([]string{"-dir", suite.dir, "-type", "AppConfig"}, &output)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(
+		"# Database host (required).\n"+
+			"DB_HOST=localhost\n"+
+			"\n"+
+			"DB_PASSWORD=\n"+
+			"\n"+
+			"SERVER_TIMEOUT=5s\n",
+		output.String(),
+	)
+}
+
+func (suite *EnvExampleCommandTestSuite) TestItWritesToAnOutputFile() {
+	outputFile := filepath.Join(suite.T().TempDir(), ".env.example")
+
+	err := run([]string{"-dir", suite.dir, "-type", "DatabaseConfig", "-o", outputFile}, nil)
+
+	suite.Require().NoError(err)
+	content, err := os.ReadFile(outputFile)
+	suite.Require().NoError(err)
+	suite.Assert().Contains(string(content), "DB_HOST=localhost\n")
+}
+
+func (suite *EnvExampleCommandTestSuite) TestItCollectsSelfReferencingTypesOnce() {
+	var output bytes.Buffer
+
+	err := run([]string{"-dir", suite.dir, "-type", "Node"}, &output)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal("NODE_NAME=\n", output.String())
+}
+
+func (suite *EnvExampleCommandTestSuite) TestItPrefixesTheKeysOfNestedStructs() {
+	var output bytes.Buffer
+
+	err := run([]string{"-dir", suite.dir, "-type", "ReplicatedConfig"}, &output)
+
+	suite.Require().NoError(err)
+	suite.Assert().Contains(output.String(), "PRIMARY_DB_HOST=localhost\n")
+	suite.Assert().Contains(output.String(), "\nPRIMARY_DB_PASSWORD=\n")
+	suite.Assert().Contains(output.String(), "REPLICA_DB_DB_HOST=localhost\n")
+	suite.Assert().NotContains(output.String(), "\nDB_HOST=")
+}
+
+func (suite *EnvExampleCommandTestSuite) TestItDocumentsTheFirstElementOfIndexedStructSlices() {
+	var output bytes.Buffer
+
+	err := run([]string{"-dir", suite.dir, "-type", "ProxyConfig"}, &output)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(
+		"# Database host (required).\n"+
+			"UPSTREAM_0_DB_HOST=localhost\n"+
+			"\n"+
+			"UPSTREAM_0_DB_PASSWORD=\n"+
+			"\n"+
+			"HOSTS=\n"+
+			"\n"+
+			"BACKUPS=\n",
+		output.String(),
+	)
+}
+
+func (suite *EnvExampleCommandTestSuite) TestItIncludesTheStructsOfOtherPackages() {
+	var output bytes.Buffer
+
+	err := run([]string{"-dir", suite.dir, "-type", "ServiceConfig"}, &output)
+
+	suite.Require().NoError(err)
+	suite.Assert().True(strings.HasPrefix(output.String(), "APP_NAME=\n"))
+	suite.Assert().Contains(output.String(), "\nSERVER_TLS_MIN_VERSION=1.2\n")
+	suite.Assert().Contains(output.String(), "\nDB_HOST=localhost\n")
+	suite.Assert().Contains(output.String(), "\nDB_PASSWORD=\n")
+}
+
+func (suite *EnvExampleCommandTestSuite) TestItFailsForUnknownTypes() {
+	testCases := []struct {
+		name    string
+		args    []string
+		message string
+	}{
+		{"missing type flag", []string{"-dir", suite.dir}, "the -type flag is required"},
+		{
+			"unknown type",
+			[]string{"-dir", suite.dir, "-type", "Missing"},
+			"struct type Missing not found",
+		},
+		{
+			"invalid type name",
+			[]string{"-dir", suite.dir, "-type", "AppConfig)(nil"},
+			`invalid type name "AppConfig)(nil"`,
+		},
+		{
+			"main package",
+			[]string{"-dir", filepath.Join("testdata", "tool"), "-type", "Config"},
+			"is a main package",
+		},
+		{
+			"missing package",
+			[]string{"-dir", filepath.Join("testdata", "missing"), "-type", "Config"},
+			"failed to list the package",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			err := run(testCase.args, &bytes.Buffer{})
+
+			suite.Require().Error(err)
+			suite.Assert().Contains(err.Error(), testCase.message)
+		})
+	}
+}
+
+func TestEnvExampleCommandSuite(t *testing.T) {
+	suite.Run(t, new(EnvExampleCommandTestSuite))
+}
@@ -0,0 +1,46 @@
+// Package app declares the config types the envexample tests generate examples from.
+package app
+
+import (
+	"time"
+
+	"github.com/golibry/go-config/config"
+	"github.com/golibry/go-config/config/presets"
+)
+
+type DatabaseConfig struct {
+	Host     string `env:"DB_HOST" default:"localhost" desc:"Database host" validate:"required"`
+	Password string `env:"DB_PASSWORD" default:"secret"`
+}
+
+type AppConfig struct {
+	Database *DatabaseConfig
+	Server   struct {
+		Timeout time.Duration `env:"SERVER_TIMEOUT" default:"5s"`
+	}
+	Name, internal string
+}
+
+type ReplicatedConfig struct {
+	Primary DatabaseConfig `envPrefix:"PRIMARY_"`
+	Replica struct {
+		Database *DatabaseConfig `envPrefix:"DB_"`
+	} `envPrefix:"REPLICA_"`
+}
+
+type ProxyConfig struct {
+	Upstreams []*DatabaseConfig `env:"UPSTREAM"`
+	Hosts     []string          `env:"HOSTS"`
+	Backups   []DatabaseConfig  `env:"BACKUPS,json"`
+}
+
+type ServiceConfig struct {
+	Name     string     `env:"APP_NAME"`
+	Server   config.TLS `envPrefix:"SERVER_"`
+	Database presets.Database
+}
+
+type Node struct {
+	Name string `env:"NODE_NAME"`
+	Next *Node
+}
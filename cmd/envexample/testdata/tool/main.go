@@ -0,0 +1,8 @@
+// Command tool is a main package, whose types cannot be imported by envexample.
+package main
+
+type Config struct {
+	Name string `env:"NAME"`
+}
+
+func main() {}
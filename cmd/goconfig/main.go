@@ -0,0 +1,285 @@
+// Command goconfig checks the env files cascade of a service without starting it, e.g. in deploy
+// pipelines. Values are resolved like config.LoadEnvVars resolves them, including the values
+// already set in the process environment, but the environment is never modified.
+//
+// Usage:
+//
+//	goconfig check [-env dev] [-dir .] [-require KEY,...] [-schema env.schema.yaml]
+//	goconfig print [-env dev] [-dir .] [-sensitive pass,secret]
+//	goconfig diff [-dir .] [-sensitive pass,secret] [-mask sha256] ENV_A ENV_B
+//
+// check reports the keys of the -require list that are missing or empty, the values violating the
+// -schema file (see config.EnvSchema) and the file values shadowed by the process environment. It
+// fails on missing or invalid keys and on invalid env files. print lists the effective values with
+// their origin, masking the sensitive ones. diff lists the keys whose effective values differ
+// between two environments, masking the sensitive ones with a SHA-256 fingerprint by default, so
+// two different secrets never look the same.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golibry/go-config/config"
+)
+
+const defaultSensitiveKeys = "pass,secret,key,token,dsn"
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "goconfig:", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches the arguments to a subcommand.
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("expected a subcommand: check, print or diff")
+	}
+
+	switch args[0] {
+	case "check":
+		return runCheck(args[1:], stdout)
+	case "print":
+		return runPrint(args[1:], stdout)
+	case "diff":
+		return runDiff(args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown subcommand %q, expected check, print or diff", args[0])
+	}
+}
+
+// runCheck reports the missing required keys and the shadowed file values of an environment.
+func runCheck(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	env := flags.String("env", "dev", "environment name")
+	dir := flags.String("dir", ".", "directory of the env files")
+	require := flags.String("require", "", "comma-separated keys that must be set and not empty")
+	schemaFile := flags.String("schema", "", "YAML or JSON env schema file to validate the values")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var schema *config.EnvSchema
+	if *schemaFile != "" {
+		content, err := os.ReadFile(*schemaFile)
+		if err != nil {
+			return err
+		}
+		if schema, err = config.ParseEnvSchema(content); err != nil {
+			return err
+		}
+	}
+
+	values, report, err := config.NewLoader().Resolve(*env, *dir)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, key := range splitList(*require) {
+		value, ok := values[key]
+		if !ok {
+			value.Value = os.Getenv(key)
+		}
+		if value.Value == "" {
+			missing = append(missing, key)
+		}
+	}
+
+	for _, origin := range report.Shadowed {
+		_, _ = fmt.Fprintf(
+			stdout,
+			"warning: %s (%s) is shadowed by the process environment\n",
+			origin.Key,
+			origin,
+		)
+	}
+	for _, key := range missing {
+		_, _ = fmt.Fprintf(stdout, "missing required key: %s\n", key)
+	}
+
+	var violations []config.SchemaViolation
+	if schema != nil {
+		err := schema.Validate(effectiveValues(schema, values))
+		var schemaErr *config.SchemaError
+		if errors.As(err, &schemaErr) {
+			violations = schemaErr.Violations
+		}
+	}
+	for _, violation := range violations {
+		_, _ = fmt.Fprintf(stdout, "invalid key: %s: %s\n", violation.Key, violation.Reason)
+	}
+
+	if len(missing) > 0 || len(violations) > 0 {
+		return fmt.Errorf(
+			"check failed for %s: %d missing keys, %d invalid keys",
+			*env,
+			len(missing),
+			len(violations),
+		)
+	}
+
+	_, _ = fmt.Fprintf(
+		stdout,
+		"ok: %d keys resolved from %d files for %s\n",
+		len(values),
+		len(report.Files),
+		*env,
+	)
+	return nil
+}
+
+// runPrint lists the effective values of an environment with their origin.
+func runPrint(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("print", flag.ContinueOnError)
+	env := flags.String("env", "dev", "environment name")
+	dir := flags.String("dir", ".", "directory of the env files")
+	sensitive := flags.String(
+		"sensitive",
+		defaultSensitiveKeys,
+		"comma-separated keywords matching the sensitive keys, whose values are masked",
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	values, _, err := config.NewLoader().Resolve(*env, *dir)
+	if err != nil {
+		return err
+	}
+
+	sensitiveKeys := splitList(*sensitive)
+	for _, key := range sortedKeys(values) {
+		_, _ = fmt.Fprintf(
+			stdout,
+			"%s=%s (%s)\n",
+			key,
+			config.MaskSensitive(key, values[key].Value, sensitiveKeys),
+			values[key].Origin,
+		)
+	}
+
+	return nil
+}
+
+// runDiff lists the keys whose effective values differ between two environments.
+func runDiff(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "directory of the env files")
+	sensitive := flags.String(
+		"sensitive",
+		defaultSensitiveKeys,
+		"comma-separated keywords matching the sensitive keys, whose values are masked",
+	)
+	maskName := flags.String(
+		"mask",
+		"sha256",
+		"masking strategy of the sensitive values: sha256, partial, full, fixed or last4",
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	mask, err := config.ParseMask(*maskName)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New("expected two environment names")
+	}
+	envA, envB := flags.Arg(0), flags.Arg(1)
+
+	valuesA, _, err := config.NewLoader().Resolve(envA, *dir)
+	if err != nil {
+		return fmt.Errorf("%s: %w", envA, err)
+	}
+	valuesB, _, err := config.NewLoader().Resolve(envB, *dir)
+	if err != nil {
+		return fmt.Errorf("%s: %w", envB, err)
+	}
+
+	keys := sortedKeys(valuesA)
+	for key := range valuesB {
+		if _, ok := valuesA[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	sensitiveKeys := splitList(*sensitive)
+	maskValue := func(key string, value config.EnvValue) string {
+		return config.MaskSensitive(key, value.Value, sensitiveKeys, config.WithMask(mask))
+	}
+
+	_, _ = fmt.Fprintf(stdout, "--- %s\n+++ %s\n", envA, envB)
+	for _, key := range keys {
+		valueA, inA := valuesA[key]
+		valueB, inB := valuesB[key]
+		switch {
+		case !inB:
+			_, _ = fmt.Fprintf(stdout, "- %s=%s\n", key, maskValue(key, valueA))
+		case !inA:
+			_, _ = fmt.Fprintf(stdout, "+ %s=%s\n", key, maskValue(key, valueB))
+		case valueA.Value != valueB.Value:
+			_, _ = fmt.Fprintf(
+				stdout,
+				"~ %s=%s -> %s\n",
+				key,
+				maskValue(key, valueA),
+				maskValue(key, valueB),
+			)
+		}
+	}
+
+	return nil
+}
+
+// effectiveValues returns the resolved values along with the process env values of the keys
+// declared by a schema but not by the env files.
+func effectiveValues(
+	schema *config.EnvSchema,
+	resolved map[string]config.EnvValue,
+) map[string]string {
+	values := make(map[string]string, len(resolved))
+	for key, value := range resolved {
+		values[key] = value.Value
+	}
+
+	for _, variable := range schema.Vars {
+		if _, ok := values[variable.Name]; !ok {
+			values[variable.Name] = os.Getenv(variable.Name)
+		}
+	}
+
+	return values
+}
+
+// sortedKeys returns the keys of resolved values in alphabetical order.
+func sortedKeys(values map[string]config.EnvValue) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// splitList splits a comma-separated flag value, ignoring empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
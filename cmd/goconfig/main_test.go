@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type GoConfigCommandTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (suite *GoConfigCommandTestSuite) SetupTest() {
+	for _, key := range []string{"GOCONFIG_HOST", "GOCONFIG_PASSWORD", "GOCONFIG_DEBUG"} {
+		suite.T().Setenv(key, "")
+		_ = os.Unsetenv(key)
+	}
+
+	suite.dir = suite.T().TempDir()
+	suite.writeEnvFile(".env", "GOCONFIG_HOST=localhost\nGOCONFIG_PASSWORD=secret\n")
+	suite.writeEnvFile(".env.dev", "GOCONFIG_DEBUG=true\n")
+	suite.writeEnvFile(".env.prod", "GOCONFIG_HOST=db.internal\nGOCONFIG_PASSWORD=prod-secret\n")
+}
+
+func (suite *GoConfigCommandTestSuite) writeEnvFile(name string, content string) {
+	fileName := filepath.Join(suite.dir, name)
+	suite.Require().NoError(os.WriteFile(fileName, []byte(content), 0644))
+}
+
+func (suite *GoConfigCommandTestSuite) TestItChecksAnEnvironment() {
+	var output bytes.Buffer
+
+	err := run([]string{"check", "-dir", suite.dir, "-require", "GOCONFIG_HOST"}, &output)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal("ok: 3 keys resolved from 2 files for dev\n", output.String())
+}
+
+func (suite *GoConfigCommandTestSuite) TestItReportsMissingKeysAndShadowedValues() {
+	suite.T().Setenv("GOCONFIG_HOST", "exported")
+	var output bytes.Buffer
+	args := []string{"check", "-env", "prod", "-dir", suite.dir}
+
+	err := run(append(args, "-require", "GOCONFIG_HOST,GOCONFIG_DEBUG"), &output)
+
+	suite.Require().Error(err)
+	suite.Assert().Equal("check failed for prod: 1 missing keys, 0 invalid keys", err.Error())
+	suite.Assert().Equal(
+		"warning: GOCONFIG_HOST ("+filepath.Join(suite.dir, ".env.prod")+":1) is shadowed by "+
+			"the process environment\n"+
+			"missing required key: GOCONFIG_DEBUG\n",
+		output.String(),
+	)
+}
+
+func (suite *GoConfigCommandTestSuite) TestItValidatesValuesAgainstASchema() {
+	suite.T().Setenv("GOCONFIG_PORT", "http")
+	schemaFile := filepath.Join(suite.dir, "env.schema.yaml")
+	schema := "vars:\n" +
+		"  - name: GOCONFIG_HOST\n    pattern: \"[a-z]+\"\n" +
+		"  - name: GOCONFIG_PORT\n    type: int\n" +
+		"  - name: GOCONFIG_DEBUG\n    type: bool\n    required: true\n"
+	suite.Require().NoError(os.WriteFile(schemaFile, []byte(schema), 0644))
+	var output bytes.Buffer
+
+	err := run([]string{"check", "-dir", suite.dir, "-schema", schemaFile}, &output)
+
+	suite.Require().Error(err)
+	suite.Assert().Equal("check failed for dev: 0 missing keys, 1 invalid keys", err.Error())
+	suite.Assert().Equal("invalid key: GOCONFIG_PORT: invalid int value\n", output.String())
+}
+
+func (suite *GoConfigCommandTestSuite) TestItFailsForInvalidEnvFiles() {
+	suite.writeEnvFile(".env.dev", "GOCONFIG_DEBUG=${GOCONFIG_MISSING:?must be set}\n")
+
+	err := run([]string{"check", "-dir", suite.dir}, &bytes.Buffer{})
+
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "GOCONFIG_MISSING")
+}
+
+func (suite *GoConfigCommandTestSuite) TestItPrintsMaskedValuesWithTheirOrigin() {
+	var output bytes.Buffer
+
+	err := run([]string{"print", "-dir", suite.dir}, &output)
+
+	suite.Require().NoError(err)
+	envFile := filepath.Join(suite.dir, ".env")
+	suite.Assert().Equal(
+		"GOCONFIG_DEBUG=true ("+filepath.Join(suite.dir, ".env.dev")+":1)\n"+
+			"GOCONFIG_HOST=localhost ("+envFile+":1)\n"+
+			"GOCONFIG_PASSWORD=s****t ("+envFile+":2)\n",
+		output.String(),
+	)
+}
+
+func (suite *GoConfigCommandTestSuite) TestItDiffsTwoEnvironments() {
+	var output bytes.Buffer
+
+	err := run([]string{"diff", "-dir", suite.dir, "dev", "prod"}, &output)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(
+		"--- dev\n"+
+			"+++ prod\n"+
+			"- GOCONFIG_DEBUG=true\n"+
+			"~ GOCONFIG_HOST=localhost -> db.internal\n"+
+			"~ GOCONFIG_PASSWORD=sha256:2bb80d53 -> sha256:e2d097e6\n",
+		output.String(),
+	)
+}
+
+func (suite *GoConfigCommandTestSuite) TestItDiffsSecretsThatMaskPartiallyAlike() {
+	suite.writeEnvFile(".env.staging", "GOCONFIG_PASSWORD=sacret\n")
+	var output bytes.Buffer
+
+	err := run([]string{"diff", "-dir", suite.dir, "dev", "staging"}, &output)
+
+	suite.Require().NoError(err)
+	suite.Assert().Contains(
+		output.String(),
+		"~ GOCONFIG_PASSWORD=sha256:2bb80d53 -> sha256:c91c6382\n",
+	)
+}
+
+func (suite *GoConfigCommandTestSuite) TestItDiffsWithAnotherMask() {
+	var output bytes.Buffer
+
+	err := run([]string{"diff", "-dir", suite.dir, "-mask", "partial", "dev", "prod"}, &output)
+
+	suite.Require().NoError(err)
+	suite.Assert().Contains(output.String(), "~ GOCONFIG_PASSWORD=s****t -> p*********t\n")
+}
+
+func (suite *GoConfigCommandTestSuite) TestItFailsForInvalidArguments() {
+	testCases := []struct {
+		name    string
+		args    []string
+		message string
+	}{
+		{"missing subcommand", nil, "expected a subcommand"},
+		{"unknown subcommand", []string{"lint"}, `unknown subcommand "lint"`},
+		{"missing diff environments", []string{"diff", "dev"}, "expected two environment names"},
+		{"unknown diff mask", []string{"diff", "-mask", "md5", "dev", "prod"}, `"md5"`},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			err := run(testCase.args, &bytes.Buffer{})
+
+			suite.Require().Error(err)
+			suite.Assert().Contains(err.Error(), testCase.message)
+		})
+	}
+}
+
+func TestGoConfigCommandSuite(t *testing.T) {
+	suite.Run(t, new(GoConfigCommandTestSuite))
+}
@@ -1,14 +1,16 @@
 package config
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io/fs"
+	"log/slog"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/joho/godotenv"
 )
 
 type Config interface {
@@ -18,45 +20,136 @@ type Config interface {
 // CompositeConfig represents a configuration that contains nested config structs.
 // It automatically populates and validates all nested structs that implement the Config interface.
 type CompositeConfig struct {
-	validator *validator.Validate
+	validator       *validator.Validate
+	loader          *Loader
+	unusedKeys      UnusedKeysPolicy
+	logger          *slog.Logger
+	envVariable     string
+	allowedEnvs     []string
+	env             string
+	report          *LoadReport
+	defaultEnv      string
+	baseDir         string
+	extraDirs       []string
+	sources         []fs.FS
+	decoders        map[reflect.Type]decodeFunc
+	separator       string
+	keyValSeparator string
+	reportProblems  ReportFunc
+	result          *LoadResult
+}
+
+// Option customizes a CompositeConfig.
+type Option func(*CompositeConfig)
+
+// WithLoader sets the Loader used to load the env files. Defaults to NewLoader().
+func WithLoader(loader *Loader) Option {
+	return func(c *CompositeConfig) {
+		c.loader = loader
+	}
+}
+
+// WithUnusedKeys sets how keys declared in env files but not read by the config tree are reported.
+func WithUnusedKeys(policy UnusedKeysPolicy) Option {
+	return func(c *CompositeConfig) {
+		c.unusedKeys = policy
+	}
+}
+
+// WithLogger sets the logger used to report warnings. Defaults to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *CompositeConfig) {
+		c.logger = logger
+	}
 }
 
 // NewCompositeConfig creates a new CompositeConfig with a validator instance.
-func NewCompositeConfig(customValidator *validator.Validate) *CompositeConfig {
+func NewCompositeConfig(customValidator *validator.Validate, opts ...Option) *CompositeConfig {
 	if customValidator == nil {
 		customValidator = validator.New()
 	}
-	return &CompositeConfig{
-		validator: customValidator,
+	c := &CompositeConfig{
+		validator:       customValidator,
+		loader:          NewLoader(),
+		logger:          slog.Default(),
+		defaultEnv:      DefaultEnv,
+		baseDir:         DefaultDir,
+		separator:       DefaultSeparator,
+		keyValSeparator: DefaultKeyValSeparator,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // PopulateAndValidate populates all nested Config structs and validates the composite struct.
-// It uses reflection to find all struct fields that implement the Config interface,
+// Fields tagged with `env` are first set from their env variable or their `default` tag. Then it
+// uses reflection to find all struct fields that implement the Config interface,
 // calls their Populate() method, and then validates the entire composite struct.
+// An empty defaultEnv or defaultAppDir falls back to the WithEnv or WithDir option value.
+// The struct is validated even when populating fails, and the errors of every phase are joined,
+// so all the problems of a config are reported at once.
 func (c *CompositeConfig) PopulateAndValidate(
 	compositeStruct interface{},
 	defaultEnv string,
 	defaultAppDir string,
 ) error {
+	if defaultEnv == "" {
+		defaultEnv = c.defaultEnv
+	}
+	if defaultAppDir == "" {
+		defaultAppDir = c.baseDir
+	}
+
+	env, err := c.resolveEnv(defaultEnv)
+	if err != nil {
+		return fmt.Errorf("failed to resolve environment: %w", err)
+	}
+	c.env = env
+
 	// Load environment variables first
-	if err := LoadEnvVars(defaultEnv, defaultAppDir); err != nil {
+	report, err := c.envLoader().Load(env, defaultAppDir)
+	if err != nil {
 		return fmt.Errorf("failed to load environment variables: %w", err)
 	}
+	c.report = report
+
+	var errs []error
+	if err := c.populateEnvFields(reflect.ValueOf(compositeStruct), "", ""); err != nil {
+		errs = append(errs, fmt.Errorf("failed to populate env fields: %w", err))
+	}
+
+	if err := c.populateNestedConfigs(compositeStruct, ""); err != nil {
+		errs = append(errs, fmt.Errorf("failed to populate nested configs: %w", err))
+	}
+
+	maskErrs := checkMaskTags(reflect.TypeOf(compositeStruct), "", make(map[reflect.Type]bool))
+	if err := errors.Join(maskErrs...); err != nil {
+		errs = append(errs, fmt.Errorf("invalid mask tags: %w", err))
+	}
 
-	if err := c.populateNestedConfigs(compositeStruct); err != nil {
-		return fmt.Errorf("failed to populate nested configs: %w", err)
+	if err := c.checkUnusedKeys(compositeStruct, report.Keys); err != nil {
+		errs = append(errs, fmt.Errorf("env files check failed: %w", err))
 	}
 
 	if err := c.validator.Struct(compositeStruct); err != nil {
-		return fmt.Errorf("config validation failed: %w", err)
+		errs = append(errs, fmt.Errorf("config validation failed: %w", err))
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// LoadReport returns the report of the env files loaded by the last PopulateAndValidate call, e.g. to
+// list the file values shadowed by the process environment.
+func (c *CompositeConfig) LoadReport() *LoadReport {
+	return c.report
 }
 
 // populateNestedConfigs uses reflection to find and populate all nested Config structs.
-func (c *CompositeConfig) populateNestedConfigs(compositeStruct interface{}) error {
+// All the nested configs are populated, even when some fail, and a *FieldError is returned for
+// every failure.
+func (c *CompositeConfig) populateNestedConfigs(compositeStruct interface{}, path string) error {
 	val := reflect.ValueOf(compositeStruct)
 	if val.Kind() == reflect.Ptr {
 		val = val.Elem()
@@ -66,6 +159,7 @@ func (c *CompositeConfig) populateNestedConfigs(compositeStruct interface{}) err
 		return fmt.Errorf("expected struct or pointer to struct, got %T", compositeStruct)
 	}
 
+	var errs []error
 	typ := val.Type()
 	for i := 0; i < val.NumField(); i++ {
 		field := val.Field(i)
@@ -76,26 +170,27 @@ func (c *CompositeConfig) populateNestedConfigs(compositeStruct interface{}) err
 			continue
 		}
 
+		fieldPath := fieldType.Name
+		if path != "" {
+			fieldPath = path + "." + fieldType.Name
+		}
+
 		// Check if field implements Config interface
 		if c.implementsConfig(field) {
 			if err := c.callPopulate(field); err != nil {
-				return fmt.Errorf("failed to populate field %s: %w", fieldType.Name, err)
+				errs = append(errs, &FieldError{Field: fieldPath, Err: err})
 			}
 		}
 
 		// Recursively handle embedded structs
 		if field.Kind() == reflect.Struct {
-			if err := c.populateNestedConfigs(field.Addr().Interface()); err != nil {
-				return fmt.Errorf(
-					"failed to populate nested struct in field %s: %w",
-					fieldType.Name,
-					err,
-				)
+			if err := c.populateNestedConfigs(field.Addr().Interface(), fieldPath); err != nil {
+				errs = append(errs, err)
 			}
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // implementsConfig checks if a reflect.Value implements the Config interface.
@@ -124,102 +219,143 @@ func (c *CompositeConfig) callPopulate(val reflect.Value) error {
 	return config.Populate()
 }
 
-// LoadEnvVars Loads the entries from env files and sets them as env variables for this process.
-// Loads each file in order: .env.{dev|prod|test}.local, .env.local, .env.{dev|prod|test}, .env.
-// The first loaded file has priority. Files will not overwrite the values of the already loaded
-// env vars (already loaded from env files or via other means).
-func LoadEnvVars(env string, appBaseDir string) error {
-	localEnvFileName := filepath.Join(appBaseDir, ".env."+env+".local")
-	if _, err := os.Stat(localEnvFileName); err == nil {
-		err := godotenv.Load(localEnvFileName)
+// DebugOption configures Debug.
+type DebugOption func(*debugState)
 
-		if err != nil {
-			return formatEnvLoadErr(localEnvFileName, err)
-		}
+// WithMask sets the strategy masking the sensitive values, e.g. MaskFixed or MaskFingerprint.
+// Defaults to MaskPartial. Fields tagged with `mask` use their own strategy.
+func WithMask(mask MaskFunc) DebugOption {
+	return func(state *debugState) {
+		state.mask = mask
 	}
+}
 
-	genericLocalFileName := filepath.Join(appBaseDir, ".env.local")
-
-	if env != "test" {
-		if _, err := os.Stat(genericLocalFileName); err == nil {
-			err := godotenv.Load(genericLocalFileName)
-
-			if err != nil {
-				return formatEnvLoadErr(genericLocalFileName, err)
-			}
-		}
-	}
-
-	genericEnvFileName := filepath.Join(appBaseDir, ".env."+env)
-	if _, err := os.Stat(genericEnvFileName); err == nil {
-		err := godotenv.Load(genericEnvFileName)
-
-		if err != nil {
-			return formatEnvLoadErr(genericEnvFileName, err)
-		}
-	}
-
-	baseEnvFileName := filepath.Join(appBaseDir, ".env")
-	if _, err := os.Stat(baseEnvFileName); err == nil {
-		err := godotenv.Load(baseEnvFileName)
-
-		if err != nil {
-			return formatEnvLoadErr(baseEnvFileName, err)
-		}
+// WithMaxDepth limits the nesting levels of structs, slices and maps output by Debug. Deeper
+// values are replaced with a "<max depth>" marker. Defaults to 0, for no limit.
+func WithMaxDepth(depth int) DebugOption {
+	return func(state *debugState) {
+		state.maxDepth = depth
 	}
-
-	return nil
 }
 
-func formatEnvLoadErr(fileName string, err error) error {
-	return fmt.Errorf(
-		"error occurred while trying to load env file: %s. Error message: %s",
-		fileName,
-		err.Error(),
-	)
+// debugState holds the settings of a Debug call, and the pointers and maps being output to detect
+// cycles.
+type debugState struct {
+	sensitiveKeys []string
+	mask          MaskFunc
+	maxDepth      int
+	visiting      map[uintptr]bool
 }
 
 // Debug transforms a config struct recursively into a string for debugging.
-// Sensitive attributes (matching keywords in sensitiveKeys) are masked with "***".
-// The sensitiveKeys slice contains keywords to check against field names (case-insensitive).
-func Debug(config interface{}, sensitiveKeys []string) string {
+// Sensitive attributes (matching keywords in sensitiveKeys) are masked, by default with
+// MaskPartial. The sensitiveKeys slice contains keywords to check against field names
+// (case-insensitive). Fields tagged with `mask` (e.g. `mask:"last4"`, see ParseMask) are masked
+// with that strategy, whatever their name. Fields whose `mask` tag names no strategy are masked
+// with MaskFull, so a typo cannot weaken the masking. PopulateAndValidate reports such typos.
+// Fields tagged with `env` are annotated with their env variable and its origin.
+// The output is deterministic: map keys are sorted, pointers are followed and pointer cycles are
+// marked with "<cycle>".
+func Debug(config interface{}, sensitiveKeys []string, opts ...DebugOption) string {
 	if config == nil {
 		return "nil"
 	}
 
+	state := &debugState{
+		sensitiveKeys: sensitiveKeys,
+		mask:          MaskPartial,
+		visiting:      make(map[uintptr]bool),
+	}
+	for _, opt := range opts {
+		opt(state)
+	}
+
 	var result strings.Builder
 	result.WriteString("Config Debug Output:\n")
-	debugValue(reflect.ValueOf(config), sensitiveKeys, &result, 0)
+	state.debugValue(reflect.ValueOf(config), &result, 0, "")
 	return result.String()
 }
 
 // debugValue recursively processes a reflect.Value and builds the debug string
-func debugValue(val reflect.Value, sensitiveKeys []string, builder *strings.Builder, indent int) {
-	// Handle pointers by dereferencing them
-	for val.Kind() == reflect.Ptr {
+func (s *debugState) debugValue(
+	val reflect.Value,
+	builder *strings.Builder,
+	indent int,
+	prefix string,
+) {
+	if s.isCycle(val) {
+		writeIndent(builder, indent)
+		builder.WriteString("<cycle>\n")
+		return
+	}
+
+	// Handle pointers and interfaces by dereferencing them, while marking them as being output
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
 		if val.IsNil() {
 			writeIndent(builder, indent)
 			builder.WriteString("nil\n")
 			return
 		}
+		if val.Kind() == reflect.Ptr {
+			defer s.visit(val.Pointer())()
+		}
 		val = val.Elem()
 	}
+	if val.Kind() == reflect.Map && !val.IsNil() {
+		defer s.visit(val.Pointer())()
+	}
 
 	switch val.Kind() {
 	case reflect.Struct:
-		debugStruct(val, sensitiveKeys, builder, indent)
+		s.debugStruct(val, builder, indent, prefix)
 	case reflect.Slice, reflect.Array:
-		debugSlice(val, sensitiveKeys, builder, indent)
+		s.debugSlice(val, builder, indent, "")
 	case reflect.Map:
-		debugMap(val, sensitiveKeys, builder, indent)
+		s.debugMap(val, builder, indent)
 	default:
 		writeIndent(builder, indent)
 		builder.WriteString(fmt.Sprintf("%v\n", val.Interface()))
 	}
 }
 
+// visit marks a pointer as being output and returns the function unmarking it.
+func (s *debugState) visit(pointer uintptr) func() {
+	s.visiting[pointer] = true
+	return func() {
+		delete(s.visiting, pointer)
+	}
+}
+
+// isCycle reports whether a value, through its pointers and interfaces, refers to a pointer or a
+// map being output.
+func (s *debugState) isCycle(val reflect.Value) bool {
+	for {
+		switch val.Kind() {
+		case reflect.Ptr, reflect.Map:
+			if !val.IsNil() && s.visiting[val.Pointer()] {
+				return true
+			}
+		}
+
+		if (val.Kind() != reflect.Ptr && val.Kind() != reflect.Interface) || val.IsNil() {
+			return false
+		}
+		val = val.Elem()
+	}
+}
+
+// isTooDeep reports whether values nested at an indent are beyond the max depth.
+func (s *debugState) isTooDeep(indent int) bool {
+	return s.maxDepth > 0 && indent >= s.maxDepth
+}
+
 // debugStruct processes struct fields recursively
-func debugStruct(val reflect.Value, sensitiveKeys []string, builder *strings.Builder, indent int) {
+func (s *debugState) debugStruct(
+	val reflect.Value,
+	builder *strings.Builder,
+	indent int,
+	prefix string,
+) {
 	typ := val.Type()
 
 	for i := 0; i < val.NumField(); i++ {
@@ -234,29 +370,60 @@ func debugStruct(val reflect.Value, sensitiveKeys []string, builder *strings.Bui
 		writeIndent(builder, indent)
 		fieldName := fieldType.Name
 		builder.WriteString(fmt.Sprintf("%s: ", fieldName))
+		annotation := originAnnotation(fieldType, prefix)
 
-		// Check if this field name matches any sensitive keywords
-		if isSensitiveField(fieldName, sensitiveKeys) {
-			fieldValue := fmt.Sprintf("%v", field.Interface())
-			maskedValue := maskSensitiveData(fieldValue)
-			builder.WriteString(maskedValue + "\n")
+		// Check if this field is tagged with a mask or its name matches any sensitive keywords
+		if mask, ok := s.fieldMask(fieldType); ok {
+			builder.WriteString(maskValue(formatScalar(field), mask) + annotation + "\n")
 			continue
 		}
 
-		if field.Kind() == reflect.Struct ||
-			field.Kind() == reflect.Slice ||
-			field.Kind() == reflect.Array ||
-			field.Kind() == reflect.Map {
+		switch {
+		case s.isCycle(field):
+			builder.WriteString("<cycle>" + annotation + "\n")
+		case isNestedValue(field) && s.isTooDeep(indent+1):
+			builder.WriteString("<max depth>" + annotation + "\n")
+		case isNestedValue(field) && defaultDecoding.isIndexedField(fieldType):
 			builder.WriteString("\n")
-			debugValue(field, sensitiveKeys, builder, indent+1)
-		} else {
-			builder.WriteString(fmt.Sprintf("%v\n", field.Interface()))
+			s.debugSlice(field, builder, indent+1, prefix+envTagName(fieldType))
+		case isNestedValue(field):
+			builder.WriteString(strings.TrimSpace(annotation) + "\n")
+			fieldPrefix := prefix + fieldType.Tag.Get("envPrefix")
+			s.debugValue(field, builder, indent+1, fieldPrefix)
+		default:
+			builder.WriteString(formatScalar(field) + annotation + "\n")
 		}
 	}
 }
 
-// debugSlice processes slice/array elements
-func debugSlice(val reflect.Value, sensitiveKeys []string, builder *strings.Builder, indent int) {
+// fieldMask returns the strategy masking a field: the one of its `mask` tag, falling back to the
+// strictest one (MaskFull) for unknown names, or the Debug one when its name matches any
+// sensitive keywords.
+func (s *debugState) fieldMask(field reflect.StructField) (MaskFunc, bool) {
+	if name, ok := field.Tag.Lookup("mask"); ok {
+		mask, err := ParseMask(name)
+		if err != nil {
+			return MaskFull, true
+		}
+		return mask, true
+	}
+
+	if isSensitiveField(field.Name, s.sensitiveKeys) {
+		return s.mask, true
+	}
+
+	return nil, false
+}
+
+// debugSlice processes slice/array elements. The fields of the elements of a slice of structs
+// populated from indexed env variables are annotated with their indexed keys, e.g.
+// UPSTREAM_0_HOST for the key UPSTREAM, which is empty for other slices.
+func (s *debugState) debugSlice(
+	val reflect.Value,
+	builder *strings.Builder,
+	indent int,
+	key string,
+) {
 	length := val.Len()
 	if length == 0 {
 		writeIndent(builder, indent)
@@ -267,19 +434,16 @@ func debugSlice(val reflect.Value, sensitiveKeys []string, builder *strings.Buil
 	for i := 0; i < length; i++ {
 		writeIndent(builder, indent)
 		builder.WriteString(fmt.Sprintf("[%d]: ", i))
-
-		elem := val.Index(i)
-		if elem.Kind() == reflect.Struct {
-			builder.WriteString("\n")
-			debugValue(elem, sensitiveKeys, builder, indent+1)
-		} else {
-			builder.WriteString(fmt.Sprintf("%v\n", elem.Interface()))
+		elemPrefix := ""
+		if key != "" {
+			elemPrefix = indexedKeyPrefix(key, i)
 		}
+		s.debugElement(val.Index(i), builder, indent, elemPrefix)
 	}
 }
 
-// debugMap processes map key-value pairs
-func debugMap(val reflect.Value, sensitiveKeys []string, builder *strings.Builder, indent int) {
+// debugMap processes map key-value pairs, sorted by key
+func (s *debugState) debugMap(val reflect.Value, builder *strings.Builder, indent int) {
 	keys := val.MapKeys()
 	if len(keys) == 0 {
 		writeIndent(builder, indent)
@@ -287,6 +451,7 @@ func debugMap(val reflect.Value, sensitiveKeys []string, builder *strings.Builde
 		return
 	}
 
+	slices.SortFunc(keys, compareMapKeys)
 	for _, key := range keys {
 		writeIndent(builder, indent)
 		keyStr := fmt.Sprintf("%v", key.Interface())
@@ -295,19 +460,107 @@ func debugMap(val reflect.Value, sensitiveKeys []string, builder *strings.Builde
 		mapVal := val.MapIndex(key)
 
 		// Check if this key matches any sensitive keywords
-		if isSensitiveField(keyStr, sensitiveKeys) {
-			mapValue := fmt.Sprintf("%v", mapVal.Interface())
-			maskedValue := maskSensitiveData(mapValue)
-			builder.WriteString(maskedValue + "\n")
+		if isSensitiveField(keyStr, s.sensitiveKeys) {
+			builder.WriteString(maskValue(formatScalar(mapVal), s.mask) + "\n")
 			continue
 		}
-		if mapVal.Kind() == reflect.Struct {
-			builder.WriteString("\n")
-			debugValue(mapVal, sensitiveKeys, builder, indent+1)
-		} else {
-			builder.WriteString(fmt.Sprintf("%v\n", mapVal.Interface()))
+		s.debugElement(mapVal, builder, indent, "")
+	}
+}
+
+// debugElement outputs a slice element or a map value after its index or key. The prefix is
+// prepended to the keys of the fields of struct elements.
+func (s *debugState) debugElement(
+	elem reflect.Value,
+	builder *strings.Builder,
+	indent int,
+	prefix string,
+) {
+	switch {
+	case s.isCycle(elem):
+		builder.WriteString("<cycle>\n")
+	case isNestedValue(elem) && s.isTooDeep(indent+1):
+		builder.WriteString("<max depth>\n")
+	case isNestedValue(elem):
+		builder.WriteString("\n")
+		s.debugValue(elem, builder, indent+1, prefix)
+	default:
+		builder.WriteString(formatScalar(elem) + "\n")
+	}
+}
+
+// isNestedValue reports whether a value is output on nested lines: structs, slices, arrays and
+// maps, through pointers and interfaces, unless they format themselves (e.g. Secret).
+func isNestedValue(val reflect.Value) bool {
+	if formatsItself(val) {
+		return false
+	}
+
+	switch indirectValue(val).Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+// formatScalar formats a value output on a single line, through its pointers and interfaces
+// unless it formats itself.
+func formatScalar(val reflect.Value) string {
+	if !formatsItself(val) {
+		val = indirectValue(val)
+	}
+
+	return fmt.Sprintf("%v", val.Interface())
+}
+
+// formatsItself reports whether a value is output with its String method, e.g. Secret or
+// time.Time. Structs with exported fields are walked instead, so their sensitive fields are
+// masked whatever their String method returns.
+func formatsItself(val reflect.Value) bool {
+	if _, isStringer := val.Interface().(fmt.Stringer); !isStringer {
+		return false
+	}
+
+	target := indirectValue(val)
+	if target.Kind() != reflect.Struct {
+		return true
+	}
+
+	for i := 0; i < target.NumField(); i++ {
+		if target.Type().Field(i).IsExported() {
+			return false
 		}
 	}
+	return true
+}
+
+// indirectValue follows the pointers and interfaces of a value, up to a nil one.
+func indirectValue(val reflect.Value) reflect.Value {
+	for (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) && !val.IsNil() {
+		val = val.Elem()
+	}
+
+	return val
+}
+
+// compareMapKeys orders map keys: numbers and strings by value, other keys by their formatting.
+func compareMapKeys(a reflect.Value, b reflect.Value) int {
+	if a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(a.Int(), b.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Uintptr:
+			return cmp.Compare(a.Uint(), b.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(a.Float(), b.Float())
+		case reflect.String:
+			return strings.Compare(a.String(), b.String())
+		}
+	}
+
+	return strings.Compare(fmt.Sprintf("%v", a.Interface()), fmt.Sprintf("%v", b.Interface()))
 }
 
 // isSensitiveField checks if a field name matches any sensitive keywords (case-insensitive)
@@ -330,29 +583,34 @@ func writeIndent(builder *strings.Builder, indent int) {
 	}
 }
 
-// maskSensitiveData masks sensitive information in a string for safe logging.
-func maskSensitiveData(data string) string {
-	if data == "" {
-		return ""
-	}
-
-	// For DSN strings, mask everything after the first character and before the last character
-	if strings.Contains(data, "@") && strings.Contains(data, ":") {
-		parts := strings.Split(data, "@")
-		if len(parts) >= 2 {
-			// Mask the user:password part
-			userPass := parts[0]
-			if len(userPass) > 2 {
-				masked := string(userPass[0]) + strings.Repeat(
-					"*",
-					len(userPass)-2,
-				) + string(userPass[len(userPass)-1])
-				return masked + "@" + parts[1]
-			}
+// MaskSensitive masks a value the way Debug does when its name (e.g. an env var name) matches one
+// of the sensitiveKeys, case-insensitively, and returns it unchanged otherwise. The masking
+// strategy can be set with WithMask.
+func MaskSensitive(
+	name string,
+	value string,
+	sensitiveKeys []string,
+	opts ...DebugOption,
+) string {
+	if isSensitiveField(name, sensitiveKeys) {
+		state := &debugState{mask: MaskPartial}
+		for _, opt := range opts {
+			opt(state)
 		}
+		return maskValue(value, state.mask)
 	}
 
-	// For other sensitive data, show the first and last character with asterisks in between
+	return value
+}
+
+// maskSensitiveData masks sensitive information in a string for safe logging. Only the password
+// and the sensitive parameters of DSNs are masked, see MaskDSN.
+func maskSensitiveData(data string) string {
+	return maskValue(data, maskPartial)
+}
+
+// maskPartial shows the first and last character of a value with asterisks in between.
+func maskPartial(data string) string {
 	if len(data) <= 2 {
 		return strings.Repeat("*", len(data))
 	}
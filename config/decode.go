@@ -0,0 +1,134 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// decodeFunc decodes an env value into a value of the type it is registered for.
+type decodeFunc func(value string) (reflect.Value, error)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// builtinDecoders decode the standard library types populated from `env` tags that are not
+// decoded by their kind.
+var builtinDecoders = map[reflect.Type]decodeFunc{
+	reflect.TypeOf(time.Duration(0)): func(value string) (reflect.Value, error) {
+		duration, err := time.ParseDuration(value)
+		return reflect.ValueOf(duration), err
+	},
+	reflect.TypeOf(url.URL{}): func(value string) (reflect.Value, error) {
+		parsed, err := url.Parse(value)
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			// The url.Error quotes the whole URL, which may hold a password.
+			return reflect.Value{}, urlErr.Err
+		}
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(*parsed), nil
+	},
+	reflect.TypeOf(time.Location{}): func(value string) (reflect.Value, error) {
+		location, err := time.LoadLocation(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(location).Elem(), nil
+	},
+}
+
+// WithDecoder registers a function decoding env values into fields of type T, e.g. a domain enum,
+// populated from `env` tags. Registered decoders take priority over the built-in ones and over the
+// encoding.TextUnmarshaler implementation of T. Fields of type *T are decoded with it too.
+func WithDecoder[T any](decode func(value string) (T, error)) Option {
+	return func(c *CompositeConfig) {
+		if c.decoders == nil {
+			c.decoders = make(map[reflect.Type]decodeFunc)
+		}
+		c.decoders[reflect.TypeOf((*T)(nil)).Elem()] = func(value string) (reflect.Value, error) {
+			decoded, err := decode(value)
+			return reflect.ValueOf(&decoded).Elem(), err
+		}
+	}
+}
+
+// decode decodes an env value into a new value of a type, using the registered decoders, the
+// encoding.TextUnmarshaler implementation of the type, the built-in decoders or its kind.
+func (c *CompositeConfig) decode(typ reflect.Type, value string) (reflect.Value, error) {
+	if decode, ok := c.decoders[typ]; ok {
+		return decode(value)
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		elem, err := c.decode(typ.Elem(), value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	}
+
+	if reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		ptr := reflect.New(typ)
+		unmarshaler := ptr.Interface().(encoding.TextUnmarshaler)
+		if err := unmarshaler.UnmarshalText([]byte(value)); err != nil {
+			return reflect.Value{}, err
+		}
+		return ptr.Elem(), nil
+	}
+
+	if decode, ok := builtinDecoders[typ]; ok {
+		return decode(value)
+	}
+
+	return decodeKind(typ, value)
+}
+
+// decodeKind decodes an env value into a new value of a type with a basic kind.
+func decodeKind(typ reflect.Type, value string) (reflect.Value, error) {
+	decoded := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		decoded.SetString(value)
+	case reflect.Slice:
+		if typ.Elem().Kind() != reflect.Uint8 {
+			return reflect.Value{}, fmt.Errorf("no decoder for type %s", typ)
+		}
+		decoded.SetBytes([]byte(value))
+	case reflect.Bool:
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		decoded.SetBool(boolean)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := strconv.ParseInt(value, 0, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		decoded.SetInt(number)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, err := strconv.ParseUint(value, 0, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		decoded.SetUint(number)
+	case reflect.Float32, reflect.Float64:
+		number, err := strconv.ParseFloat(value, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		decoded.SetFloat(number)
+	default:
+		return reflect.Value{}, fmt.Errorf("no decoder for type %s", typ)
+	}
+
+	return decoded, nil
+}
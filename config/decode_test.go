@@ -0,0 +1,135 @@
+package config
+
+import (
+	"errors"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DecodeTestSuite struct {
+	suite.Suite
+}
+
+type decodeColor int
+
+const (
+	decodeRed decodeColor = iota + 1
+	decodeBlue
+)
+
+func parseDecodeColor(value string) (decodeColor, error) {
+	switch strings.ToLower(value) {
+	case "red":
+		return decodeRed, nil
+	case "blue":
+		return decodeBlue, nil
+	default:
+		return 0, errors.New("unknown color")
+	}
+}
+
+func (suite *DecodeTestSuite) TestItDecodesBuiltinTypes() {
+	c := NewCompositeConfig(nil)
+	apiURL, _ := url.Parse("https://api.local/v1")
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	testCases := []struct {
+		value    string
+		expected interface{}
+	}{
+		{"text", "text"},
+		{"raw", []byte("raw")},
+		{"true", true},
+		{"-42", int8(-42)},
+		{"0x10", 16},
+		{"42", uint16(42)},
+		{"0.5", float32(0.5)},
+		{"1m30s", 90 * time.Second},
+		{"https://api.local/v1", *apiURL},
+		{"https://api.local/v1", apiURL},
+		{"Europe/Paris", paris},
+		{"10.0.0.1", net.ParseIP("10.0.0.1")},
+		{"warn", slog.LevelWarn},
+		{"123456789012345678901234567890", mustBigInt("123456789012345678901234567890")},
+		{"7", func() *int { number := 7; return &number }()},
+	}
+
+	for _, testCase := range testCases {
+		typ := reflect.TypeOf(testCase.expected)
+		suite.Run(typ.String(), func() {
+			decoded, err := c.decode(typ, testCase.value)
+
+			suite.Require().NoError(err)
+			suite.Assert().Equal(testCase.expected, decoded.Interface())
+		})
+	}
+}
+
+func (suite *DecodeTestSuite) TestItDecodesRegisteredTypes() {
+	c := NewCompositeConfig(
+		nil,
+		WithDecoder(parseDecodeColor),
+		WithDecoder(func(value string) (slog.Level, error) {
+			return slog.LevelError, nil
+		}),
+	)
+
+	color, err := c.decode(reflect.TypeOf(decodeColor(0)), "Blue")
+	suite.Require().NoError(err)
+	suite.Assert().Equal(decodeBlue, color.Interface())
+
+	colorPtr, err := c.decode(reflect.TypeOf((*decodeColor)(nil)), "red")
+	suite.Require().NoError(err)
+	suite.Assert().Equal(decodeRed, *colorPtr.Interface().(*decodeColor))
+
+	level, err := c.decode(reflect.TypeOf(slog.LevelInfo), "info")
+	suite.Require().NoError(err)
+	suite.Assert().Equal(slog.LevelError, level.Interface(), "registered decoders take priority")
+}
+
+func (suite *DecodeTestSuite) TestItFailsForInvalidValuesAndUnsupportedTypes() {
+	c := NewCompositeConfig(nil, WithDecoder(parseDecodeColor))
+
+	testCases := []struct {
+		name    string
+		typ     reflect.Type
+		value   string
+		message string
+	}{
+		{"int", reflect.TypeOf(0), "ten", "invalid syntax"},
+		{"int8 range", reflect.TypeOf(int8(0)), "300", "value out of range"},
+		{"duration", reflect.TypeOf(time.Second), "10", "missing unit"},
+		{"url", reflect.TypeOf(url.URL{}), "postgres://app:S3cr%zzet@db/app", "invalid URL escape"},
+		{"location", reflect.TypeOf(time.Location{}), "Mars/Base", "unknown time zone"},
+		{"ip", reflect.TypeOf(net.IP{}), "10.0", "invalid IP address"},
+		{"registered", reflect.TypeOf(decodeColor(0)), "green", "unknown color"},
+		{"unsupported", reflect.TypeOf(struct{}{}), "{}", "no decoder for type struct {}"},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			_, err := c.decode(testCase.typ, testCase.value)
+
+			suite.Require().Error(err)
+			suite.Assert().Contains(err.Error(), testCase.message)
+			suite.Assert().NotContains(err.Error(), "S3cr")
+		})
+	}
+}
+
+func mustBigInt(value string) *big.Int {
+	number, _ := new(big.Int).SetString(value, 10)
+	return number
+}
+
+func TestDecodeSuite(t *testing.T) {
+	suite.Run(t, new(DecodeTestSuite))
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FieldSpec describes a config field declaring its env variable with an `env` tag, as collected
+// from the field's tags: `env`, `default` (the documented default value), `desc` (a description)
+// and `validate`.
+type FieldSpec struct {
+	// Path is the dotted Go field path, e.g. "Database.Host".
+	Path string
+	// Env is the env variable name.
+	Env string
+	// Type is the Go type of the field, e.g. "int" or "time.Duration".
+	Type        string
+	Default     string
+	Description string
+	// Validate holds the validator rules of the field.
+	Validate string
+	// Required reports whether the validator rules include "required".
+	Required bool
+}
+
+// Describe returns the specs of every `env` tagged field of a config struct tree, in declaration
+// order. The config can be a struct, a pointer to a struct or a nil pointer to a struct type.
+// Slices of structs populated from indexed env variables are described by the fields of their
+// first element, e.g. UPSTREAM_0_HOST at path "Upstreams[0].Host" for an `env:"UPSTREAM"` slice.
+func Describe(config interface{}) []FieldSpec {
+	if config == nil {
+		return nil
+	}
+
+	var specs []FieldSpec
+	describeType(reflect.TypeOf(config), "", "", make(map[reflect.Type]bool), &specs)
+	return specs
+}
+
+// describeType appends the specs of the `env` tagged fields of a struct type, recursively. The
+// prefix, extended by the `envPrefix` tags of the nested struct fields, is prepended to the keys.
+// The struct types being described are skipped, so self-referencing types are described once.
+func describeType(
+	typ reflect.Type,
+	path string,
+	prefix string,
+	describing map[reflect.Type]bool,
+	specs *[]FieldSpec,
+) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct || describing[typ] {
+		return
+	}
+	describing[typ] = true
+	defer delete(describing, typ)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		spec, tagged := FieldSpecFromTag(fieldPath, field.Type.String(), field.Tag)
+		if !tagged {
+			fieldPrefix := prefix + field.Tag.Get("envPrefix")
+			describeType(field.Type, fieldPath, fieldPrefix, describing, specs)
+			continue
+		}
+
+		spec.Env = prefix + spec.Env
+		if defaultDecoding.isIndexedField(field) {
+			elemPrefix := indexedKeyPrefix(spec.Env, 0)
+			describeType(field.Type.Elem(), fieldPath+"[0]", elemPrefix, describing, specs)
+			continue
+		}
+
+		*specs = append(*specs, spec)
+	}
+}
+
+// FieldSpecFromTag creates the spec of a field from its path, Go type and tags. It returns false
+// when the field has no `env` tag. It lets tools collecting fields without reflection (e.g. from
+// source code) build the same specs as Describe.
+func FieldSpecFromTag(path string, goType string, tag reflect.StructTag) (FieldSpec, bool) {
+	env := envTagName(reflect.StructField{Tag: tag})
+	if env == "" {
+		return FieldSpec{}, false
+	}
+
+	spec := FieldSpec{
+		Path:        path,
+		Env:         env,
+		Type:        goType,
+		Default:     tag.Get("default"),
+		Description: tag.Get("desc"),
+		Validate:    tag.Get("validate"),
+	}
+
+	for _, rule := range strings.Split(spec.Validate, ",") {
+		if strings.TrimSpace(rule) == "required" {
+			spec.Required = true
+		}
+	}
+
+	return spec, true
+}
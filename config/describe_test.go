@@ -0,0 +1,103 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DescribeTestSuite struct {
+	suite.Suite
+}
+
+type describeDatabaseConfig struct {
+	Host     string        `env:"DB_HOST" default:"localhost" desc:"Database host" validate:"required"`
+	Password string        `env:"DB_PASSWORD" validate:"required,min=8"`
+	Timeout  time.Duration `env:"DB_TIMEOUT" default:"5s"`
+	pool     int
+}
+
+type describeAppConfig struct {
+	Database *describeDatabaseConfig
+	Cache    struct {
+		URL string `env:"CACHE_URL,json" desc:"Cache URL" validate:"required_if=Enabled true"`
+	}
+	Name string
+}
+
+type describeNode struct {
+	Name string `env:"NODE_NAME"`
+	Next *describeNode
+}
+
+func (suite *DescribeTestSuite) TestItDescribesEnvTaggedFields() {
+	expected := []FieldSpec{
+		{
+			Path:        "Database.Host",
+			Env:         "DB_HOST",
+			Type:        "string",
+			Default:     "localhost",
+			Description: "Database host",
+			Validate:    "required",
+			Required:    true,
+		},
+		{
+			Path:     "Database.Password",
+			Env:      "DB_PASSWORD",
+			Type:     "string",
+			Validate: "required,min=8",
+			Required: true,
+		},
+		{Path: "Database.Timeout", Env: "DB_TIMEOUT", Type: "time.Duration", Default: "5s"},
+		{
+			Path:        "Cache.URL",
+			Env:         "CACHE_URL",
+			Type:        "string",
+			Description: "Cache URL",
+			Validate:    "required_if=Enabled true",
+		},
+	}
+
+	suite.Assert().Equal(expected, Describe((*describeAppConfig)(nil)))
+	suite.Assert().Equal(expected, Describe(describeAppConfig{}))
+	suite.Assert().Nil(Describe(nil))
+}
+
+func (suite *DescribeTestSuite) TestItDescribesSelfReferencingTypesOnce() {
+	suite.Assert().Equal(
+		[]FieldSpec{{Path: "Name", Env: "NODE_NAME", Type: "string"}},
+		Describe(&describeNode{}),
+	)
+}
+
+type describeUpstream struct {
+	Host string `env:"HOST" validate:"required"`
+	Port int    `env:"PORT" default:"80"`
+}
+
+type describeProxyConfig struct {
+	Upstreams []describeUpstream  `env:"UPSTREAM"`
+	Backups   []*describeUpstream `env:"BACKUP,json"`
+}
+
+func (suite *DescribeTestSuite) TestItDescribesTheFirstElementOfIndexedStructSlices() {
+	suite.Assert().Equal(
+		[]FieldSpec{
+			{
+				Path:     "Upstreams[0].Host",
+				Env:      "UPSTREAM_0_HOST",
+				Type:     "string",
+				Validate: "required",
+				Required: true,
+			},
+			{Path: "Upstreams[0].Port", Env: "UPSTREAM_0_PORT", Type: "int", Default: "80"},
+			{Path: "Backups", Env: "BACKUP", Type: "[]*config.describeUpstream"},
+		},
+		Describe(&describeProxyConfig{}),
+	)
+}
+
+func TestDescribeSuite(t *testing.T) {
+	suite.Run(t, new(DescribeTestSuite))
+}
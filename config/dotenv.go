@@ -0,0 +1,176 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// dotenvEntry is a key declaration parsed from env file content.
+// Value is not interpolated yet; Expand is false for single-quoted values, which are literal.
+// EndLine differs from Line for quoted values spanning lines and Comment holds the inline comment.
+type dotenvEntry struct {
+	Key     string
+	Value   string
+	File    string
+	Line    int
+	EndLine int
+	Expand  bool
+	Export  bool
+	Comment string
+}
+
+// origin returns the file origin of the entry.
+func (e dotenvEntry) origin() Origin {
+	return Origin{Key: e.Key, Source: SourceFile, File: e.File, Line: e.Line}
+}
+
+// parseDotenv parses env file content into entries, in declaration order.
+// It supports comments, the "export" prefix, "KEY=value" and "KEY: value" declarations, inline
+// comments after unquoted values and single or double-quoted values, which may span lines.
+// Double-quoted values support the \n, \r, \t, \" and \\ escapes.
+func parseDotenv(content []byte) ([]dotenvEntry, error) {
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+
+	var entries []dotenvEntry
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimLeftFunc(lines[i], unicode.IsSpace)
+		if strings.TrimSpace(line) == "" || line[0] == '#' {
+			continue
+		}
+
+		lineNumber := i + 1
+		export := false
+		if rest, found := strings.CutPrefix(line, "export"); found && rest != "" &&
+			(rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimLeft(rest, " \t")
+			export = true
+		}
+
+		separator := strings.IndexAny(line, "=:")
+		if separator == -1 {
+			return nil, fmt.Errorf("line %d: missing \"=\" in %q", lineNumber, line)
+		}
+
+		key := strings.TrimSpace(line[:separator])
+		if !isValidEnvKey(key) {
+			return nil, fmt.Errorf("line %d: invalid key %q", lineNumber, key)
+		}
+
+		entry := dotenvEntry{
+			Key:     key,
+			Line:    lineNumber,
+			EndLine: lineNumber,
+			Expand:  true,
+			Export:  export,
+		}
+		rest := strings.TrimLeft(line[separator+1:], " \t")
+		if rest == "" || (rest[0] != '"' && rest[0] != '\'') {
+			entry.Value, entry.Comment = unquotedValue(rest)
+			entries = append(entries, entry)
+			continue
+		}
+
+		quote := rest[0]
+		rest = rest[1:]
+		end := closingQuoteIndex(rest, quote)
+		for end == -1 {
+			if i+1 >= len(lines) {
+				return nil, fmt.Errorf("line %d: unterminated quoted value for %s", lineNumber, key)
+			}
+			i++
+			rest += "\n" + lines[i]
+			end = closingQuoteIndex(rest, quote)
+		}
+
+		entry.Value = rest[:end]
+		if quote == '"' {
+			entry.Value = unescapeDoubleQuoted(entry.Value)
+		} else {
+			entry.Value = strings.ReplaceAll(entry.Value, `\'`, `'`)
+			entry.Expand = false
+		}
+
+		trailing := strings.TrimSpace(rest[end+1:])
+		if trailing != "" && trailing[0] != '#' {
+			return nil, fmt.Errorf("line %d: unexpected %q after quoted value", i+1, trailing)
+		}
+
+		entry.EndLine = i + 1
+		entry.Comment = trailing
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// isValidEnvKey reports whether a key contains only letters, digits, "_" and ".".
+func isValidEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for _, char := range key {
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) && char != '_' && char != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// unquotedValue trims an unquoted value and splits off its inline comment (" # comment").
+func unquotedValue(value string) (string, string) {
+	comment := ""
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+			value, comment = value[:i], strings.TrimSpace(value[i:])
+			break
+		}
+	}
+
+	return strings.TrimSpace(value), comment
+}
+
+// closingQuoteIndex returns the index of the first unescaped quote in value or -1.
+func closingQuoteIndex(value string, quote byte) int {
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' {
+			i++
+			continue
+		}
+		if value[i] == quote {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// unescapeDoubleQuoted replaces the escape sequences of a double-quoted value. The "\$" escape is
+// kept so that interpolation treats it as a literal dollar sign.
+func unescapeDoubleQuoted(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			builder.WriteByte(value[i])
+			continue
+		}
+
+		i++
+		switch value[i] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 't':
+			builder.WriteByte('\t')
+		case '$':
+			builder.WriteString(`\$`)
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+
+	return builder.String()
+}
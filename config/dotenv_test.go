@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DotenvTestSuite struct {
+	suite.Suite
+}
+
+func (suite *DotenvTestSuite) TestItCanParseDotenvContent() {
+	content := "# comment\r\n" +
+		"export PLAIN=value # inline comment\n" +
+		"YAML_STYLE: yaml\n" +
+		"\n" +
+		"DOUBLE=\"line\\nnext \\\"quoted\\\" \\$HOME\"\n" +
+		"SINGLE='$HOME # not a comment'\n" +
+		"MULTI=\"first\n" +
+		"second\"\n" +
+		"EMPTY=\n" +
+		"HASH=a#b\n"
+
+	entries, err := parseDotenv([]byte(content))
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(
+		[]dotenvEntry{
+			{
+				Key:     "PLAIN",
+				Value:   "value",
+				Line:    2,
+				EndLine: 2,
+				Expand:  true,
+				Export:  true,
+				Comment: "# inline comment",
+			},
+			{Key: "YAML_STYLE", Value: "yaml", Line: 3, EndLine: 3, Expand: true},
+			{
+				Key:     "DOUBLE",
+				Value:   "line\nnext \"quoted\" \\$HOME",
+				Line:    5,
+				EndLine: 5,
+				Expand:  true,
+			},
+			{Key: "SINGLE", Value: "$HOME # not a comment", Line: 6, EndLine: 6},
+			{Key: "MULTI", Value: "first\nsecond", Line: 7, EndLine: 8, Expand: true},
+			{Key: "EMPTY", Value: "", Line: 9, EndLine: 9, Expand: true},
+			{Key: "HASH", Value: "a#b", Line: 10, EndLine: 10, Expand: true},
+		},
+		entries,
+	)
+}
+
+func (suite *DotenvTestSuite) TestItRejectsInvalidDotenvContent() {
+	testCases := []struct {
+		name    string
+		content string
+		message string
+	}{
+		{"missing separator", "KEY\n", "line 1: missing \"=\""},
+		{"invalid key", "\nBAD-KEY=value\n", "line 2: invalid key \"BAD-KEY\""},
+		{"unterminated quote", "KEY=\"value\nnext\n", "line 1: unterminated quoted value for KEY"},
+		{"trailing content", "KEY='value' extra\n", "line 1: unexpected \"extra\""},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			_, err := parseDotenv([]byte(testCase.content))
+
+			suite.Require().Error(err)
+			suite.Assert().Contains(err.Error(), testCase.message)
+		})
+	}
+}
+
+func TestDotenvSuite(t *testing.T) {
+	suite.Run(t, new(DotenvTestSuite))
+}
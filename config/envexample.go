@@ -0,0 +1,53 @@
+package config
+
+import (
+	"io"
+	"strings"
+)
+
+// WriteEnvExample writes a commented .env.example file declaring every field spec, in order, with
+// its documented default as value. Each declaration is preceded by the field description and
+// whether it is required. Values of sensitive fields (whose env or field name matches one of the
+// sensitiveKeys, case-insensitive) are left blank.
+func WriteEnvExample(w io.Writer, specs []FieldSpec, sensitiveKeys []string) error {
+	var builder strings.Builder
+	for i, spec := range specs {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		for _, line := range strings.Split(exampleComment(spec), "\n") {
+			if line != "" {
+				builder.WriteString("# " + line + "\n")
+			}
+		}
+
+		value := spec.Default
+		if isSensitiveSpec(spec, sensitiveKeys) {
+			value = ""
+		}
+		builder.WriteString(formatEnvDeclaration(dotenvEntry{Key: spec.Env}, value))
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// exampleComment returns the comment describing a field spec in a .env.example file.
+func exampleComment(spec FieldSpec) string {
+	if !spec.Required {
+		return spec.Description
+	}
+
+	if spec.Description == "" {
+		return "Required."
+	}
+
+	return strings.TrimSuffix(spec.Description, ".") + " (required)."
+}
+
+// isSensitiveSpec reports whether the env or field name of a spec matches a sensitive keyword.
+func isSensitiveSpec(spec FieldSpec, sensitiveKeys []string) bool {
+	fieldName := spec.Path[strings.LastIndex(spec.Path, ".")+1:]
+	return isSensitiveField(spec.Env, sensitiveKeys) || isSensitiveField(fieldName, sensitiveKeys)
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvExampleTestSuite struct {
+	suite.Suite
+}
+
+func (suite *EnvExampleTestSuite) TestItWritesACommentedEnvExample() {
+	var output bytes.Buffer
+
+	err := WriteEnvExample(&output, Describe(&describeAppConfig{}), []string{"password"})
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(
+		"# Database host (required).\n"+
+			"DB_HOST=localhost\n"+
+			"\n"+
+			"# Required.\n"+
+			"DB_PASSWORD=\n"+
+			"\n"+
+			"DB_TIMEOUT=5s\n"+
+			"\n"+
+			"# Cache URL\n"+
+			"CACHE_URL=\n",
+		output.String(),
+	)
+}
+
+func (suite *EnvExampleTestSuite) TestItBlanksSensitiveDefaultsAndQuotesValues() {
+	specs := []FieldSpec{
+		{Path: "Token", Env: "API_TOKEN", Default: "dev-token"},
+		{Path: "Greeting", Env: "GREETING", Default: "hello world", Description: "Multi\nline"},
+	}
+	var output bytes.Buffer
+
+	err := WriteEnvExample(&output, specs, []string{"token"})
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(
+		"API_TOKEN=\n\n# Multi\n# line\nGREETING=\"hello world\"\n",
+		output.String(),
+	)
+}
+
+func TestEnvExampleSuite(t *testing.T) {
+	suite.Run(t, new(EnvExampleTestSuite))
+}
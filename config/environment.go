@@ -0,0 +1,64 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownEnv is returned when the resolved environment name is not in the allowed set.
+var ErrUnknownEnv = errors.New("unknown environment")
+
+// DefaultEnvs is the allowed environment set used by WithEnvDetection when none is given.
+var DefaultEnvs = []string{"dev", "prod", "test"}
+
+// WithEnvDetection resolves the environment name from the given env variable (e.g. APP_ENV),
+// falling back to the default passed to PopulateAndValidate when it is not set. The resolved name
+// must be one of the allowed environments, DefaultEnvs when none is given. The variable is read
+// from the process environment, before any env file is loaded. The allowed set is copied, so
+// later changes to DefaultEnvs or to the given slice do not affect the option.
+func WithEnvDetection(variable string, allowed ...string) Option {
+	if len(allowed) == 0 {
+		allowed = DefaultEnvs
+	}
+	allowed = slices.Clone(allowed)
+
+	return func(c *CompositeConfig) {
+		c.envVariable = variable
+		c.allowedEnvs = allowed
+	}
+}
+
+// Env returns the environment name used by the last PopulateAndValidate call, so application code
+// can branch on it.
+func (c *CompositeConfig) Env() string {
+	return c.env
+}
+
+// resolveEnv returns the environment name to load, detected from the configured variable if any.
+func (c *CompositeConfig) resolveEnv(defaultEnv string) (string, error) {
+	if c.envVariable == "" {
+		return defaultEnv, nil
+	}
+
+	env := defaultEnv
+	source := "the default, " + c.envVariable + " is not set"
+	if value := strings.TrimSpace(os.Getenv(c.envVariable)); value != "" {
+		env = value
+		source = "from " + c.envVariable
+	}
+
+	if !slices.Contains(c.allowedEnvs, env) {
+		return "", fmt.Errorf(
+			"%w: %q (%s), allowed: %s",
+			ErrUnknownEnv,
+			env,
+			source,
+			strings.Join(c.allowedEnvs, ", "),
+		)
+	}
+
+	return env, nil
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvironmentTestSuite struct {
+	suite.Suite
+}
+
+type environmentConfig struct {
+	Name string
+}
+
+func (suite *EnvironmentTestSuite) SetupTest() {
+	suite.T().Setenv("ENVIRONMENT_APP_ENV", "")
+	_ = os.Unsetenv("ENVIRONMENT_APP_ENV")
+}
+
+func (suite *EnvironmentTestSuite) TestItResolvesTheEnvironmentFromTheConfiguredVariable() {
+	suite.T().Setenv("ENVIRONMENT_APP_ENV", "prod")
+	compositeConfig := NewCompositeConfig(nil, WithEnvDetection("ENVIRONMENT_APP_ENV"))
+
+	err := compositeConfig.PopulateAndValidate(&environmentConfig{}, "dev", suite.T().TempDir())
+
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("prod", compositeConfig.Env())
+}
+
+func (suite *EnvironmentTestSuite) TestItCopiesTheAllowedEnvironments() {
+	allowed := []string{"dev", "staging"}
+	defaultConfig := NewCompositeConfig(nil, WithEnvDetection("ENVIRONMENT_APP_ENV"))
+	customConfig := NewCompositeConfig(nil, WithEnvDetection("ENVIRONMENT_APP_ENV", allowed...))
+
+	defaultConfig.allowedEnvs[0] = "qa"
+	customConfig.allowedEnvs[0] = "qa"
+
+	suite.Assert().Equal([]string{"dev", "prod", "test"}, DefaultEnvs)
+	suite.Assert().Equal([]string{"dev", "staging"}, allowed)
+}
+
+func (suite *EnvironmentTestSuite) TestItFallsBackToTheDefaultEnvironment() {
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{"without detection", nil},
+		{"variable not set", []Option{WithEnvDetection("ENVIRONMENT_APP_ENV", "dev", "staging")}},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			compositeConfig := NewCompositeConfig(nil, testCase.opts...)
+
+			err := compositeConfig.PopulateAndValidate(&environmentConfig{}, "dev", suite.T().TempDir())
+
+			suite.Assert().NoError(err)
+			suite.Assert().Equal("dev", compositeConfig.Env())
+		})
+	}
+}
+
+func (suite *EnvironmentTestSuite) TestItRejectsEnvironmentsOutsideTheAllowedSet() {
+	suite.T().Setenv("ENVIRONMENT_APP_ENV", "staging")
+	compositeConfig := NewCompositeConfig(nil, WithEnvDetection("ENVIRONMENT_APP_ENV"))
+
+	err := compositeConfig.PopulateAndValidate(&environmentConfig{}, "dev", suite.T().TempDir())
+
+	suite.Assert().True(errors.Is(err, ErrUnknownEnv))
+	suite.Assert().Contains(err.Error(), `"staging" (from ENVIRONMENT_APP_ENV), allowed: dev, prod, test`)
+}
+
+func (suite *EnvironmentTestSuite) TestItRejectsDefaultEnvironmentsOutsideTheAllowedSet() {
+	compositeConfig := NewCompositeConfig(nil, WithEnvDetection("ENVIRONMENT_APP_ENV"))
+
+	err := compositeConfig.PopulateAndValidate(&environmentConfig{}, "qa", suite.T().TempDir())
+
+	suite.Assert().True(errors.Is(err, ErrUnknownEnv))
+	suite.Assert().Contains(
+		err.Error(),
+		`"qa" (the default, ENVIRONMENT_APP_ENV is not set), allowed: dev, prod, test`,
+	)
+}
+
+func TestEnvironmentSuite(t *testing.T) {
+	suite.Run(t, new(EnvironmentTestSuite))
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrReferenceCycle is returned when env file values reference each other in a cycle.
+	ErrReferenceCycle = errors.New("env var reference cycle")
+	// ErrRequiredVar is returned when a ${VAR:?message} reference targets an unset or empty var.
+	ErrRequiredVar = errors.New("required env var is not set")
+)
+
+// envResolver interpolates env file values across files. Values from the process environment take
+// priority over file values and are used literally.
+type envResolver struct {
+	lookupEnv func(string) (string, bool)
+	entries   map[string]dotenvEntry
+	keys      []string
+	resolved  map[string]string
+	resolving []string
+}
+
+// newEnvResolver creates a resolver for the entries of env files given in priority order.
+// The first file declaring a key wins; within a file, the last declaration wins.
+func newEnvResolver(files [][]dotenvEntry, lookupEnv func(string) (string, bool)) *envResolver {
+	r := &envResolver{
+		lookupEnv: lookupEnv,
+		entries:   make(map[string]dotenvEntry),
+		resolved:  make(map[string]string),
+	}
+
+	for _, entries := range files {
+		fileEntries := make(map[string]dotenvEntry)
+		for _, entry := range entries {
+			fileEntries[entry.Key] = entry
+		}
+
+		for _, entry := range entries {
+			if _, exists := r.entries[entry.Key]; exists {
+				continue
+			}
+			r.entries[entry.Key] = fileEntries[entry.Key]
+			r.keys = append(r.keys, entry.Key)
+		}
+	}
+
+	return r
+}
+
+// resolveAll returns the interpolated value of every file key missing from the process environment.
+// Errors mention the key and the file and line declaring it.
+func (r *envResolver) resolveAll() (map[string]string, error) {
+	values := make(map[string]string)
+	for _, key := range r.keys {
+		if _, exists := r.lookupEnv(key); exists {
+			continue
+		}
+
+		value, err := r.value(key)
+		if err != nil {
+			return nil, fmt.Errorf("%s (%s): %w", key, r.entries[key].origin(), err)
+		}
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// value returns the interpolated value of a key or an empty string when it is not set anywhere.
+func (r *envResolver) value(key string) (string, error) {
+	if value, exists := r.lookupEnv(key); exists {
+		return value, nil
+	}
+
+	entry, exists := r.entries[key]
+	if !exists {
+		return "", nil
+	}
+
+	if value, done := r.resolved[key]; done {
+		return value, nil
+	}
+
+	if start := slices.Index(r.resolving, key); start != -1 {
+		cycle := append(slices.Clone(r.resolving[start:]), key)
+		return "", fmt.Errorf("%w: %s", ErrReferenceCycle, strings.Join(cycle, " -> "))
+	}
+
+	value := entry.Value
+	if entry.Expand {
+		r.resolving = append(r.resolving, key)
+		expanded, err := r.expand(value)
+		r.resolving = r.resolving[:len(r.resolving)-1]
+		if err != nil {
+			return "", err
+		}
+		value = expanded
+	}
+
+	r.resolved[key] = value
+	return value, nil
+}
+
+// expand replaces the $VAR, ${VAR}, ${VAR:-default} and ${VAR:?message} references of a value.
+// An escaped "\$" is kept as a literal dollar sign.
+func (r *envResolver) expand(value string) (string, error) {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		char := value[i]
+		if char == '\\' && i+1 < len(value) && value[i+1] == '$' {
+			builder.WriteByte('$')
+			i++
+			continue
+		}
+
+		if char != '$' || i+1 == len(value) {
+			builder.WriteByte(char)
+			continue
+		}
+
+		if value[i+1] == '{' {
+			end := closingBraceIndex(value, i+1)
+			if end == -1 {
+				return "", fmt.Errorf("unterminated reference in %q", value)
+			}
+
+			expanded, err := r.expandReference(value[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+			builder.WriteString(expanded)
+			i = end
+			continue
+		}
+
+		nameEnd := i + 1
+		for nameEnd < len(value) && isEnvNameChar(value[nameEnd]) {
+			nameEnd++
+		}
+
+		if nameEnd == i+1 {
+			builder.WriteByte(char)
+			continue
+		}
+
+		expanded, err := r.value(value[i+1 : nameEnd])
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(expanded)
+		i = nameEnd - 1
+	}
+
+	return builder.String(), nil
+}
+
+// expandReference expands the content of a ${...} reference.
+func (r *envResolver) expandReference(reference string) (string, error) {
+	name, modifier, hasModifier := strings.Cut(reference, ":")
+	value, err := r.value(name)
+	if err != nil || !hasModifier || value != "" {
+		return value, err
+	}
+
+	switch {
+	case strings.HasPrefix(modifier, "-"):
+		return r.expand(modifier[1:])
+	case strings.HasPrefix(modifier, "?"):
+		message := modifier[1:]
+		if message == "" {
+			message = "must be set"
+		}
+		return "", fmt.Errorf("%w: %s: %s", ErrRequiredVar, name, message)
+	default:
+		return "", fmt.Errorf("unsupported reference modifier in ${%s}", reference)
+	}
+}
+
+// closingBraceIndex returns the index of the brace closing the one at open, or -1.
+func closingBraceIndex(value string, open int) int {
+	depth := 0
+	for i := open; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// isEnvNameChar reports whether a character can be part of an unbraced $VAR reference.
+func isEnvNameChar(char byte) bool {
+	return char == '_' || (char >= '0' && char <= '9') ||
+		(char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type InterpolationTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (suite *InterpolationTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *InterpolationTestSuite) TestItResolvesReferencesAcrossFilesByPriority() {
+	unsetEnv(suite.T(), "INTERP_HOME", "INTERP_DATA", "INTERP_LOGS", "INTERP_CACHE", "INTERP_RAW")
+	suite.T().Setenv("INTERP_USER", "exported")
+	writeEnvFile(suite.T(), suite.dir, ".env.test.local", "INTERP_HOME=/srv/local\n")
+	writeEnvFile(
+		suite.T(),
+		suite.dir,
+		".env",
+		"INTERP_HOME=/srv/base\n"+
+			"INTERP_DATA=${INTERP_HOME}/data\n"+
+			"INTERP_LOGS=$INTERP_DATA/logs/$INTERP_USER\n"+
+			"INTERP_CACHE=${INTERP_MISSING:-${INTERP_HOME}/cache}\n"+
+			"INTERP_RAW='${INTERP_HOME}'\n",
+	)
+
+	suite.Require().NoError(LoadEnvVars("test", suite.dir))
+
+	suite.Assert().Equal("/srv/local/data", os.Getenv("INTERP_DATA"))
+	suite.Assert().Equal("/srv/local/data/logs/exported", os.Getenv("INTERP_LOGS"))
+	suite.Assert().Equal("/srv/local/cache", os.Getenv("INTERP_CACHE"))
+	suite.Assert().Equal("${INTERP_HOME}", os.Getenv("INTERP_RAW"))
+}
+
+func (suite *InterpolationTestSuite) TestItKeepsEscapedDollarSigns() {
+	unsetEnv(suite.T(), "INTERP_PRICE")
+	writeEnvFile(suite.T(), suite.dir, ".env", "INTERP_PRICE=\\$5 and \"\\$6\"\n")
+
+	suite.Require().NoError(LoadEnvVars("test", suite.dir))
+
+	suite.Assert().Equal("$5 and \"$6\"", os.Getenv("INTERP_PRICE"))
+}
+
+func (suite *InterpolationTestSuite) TestItFailsOnReferenceErrors() {
+	testCases := []struct {
+		name     string
+		content  string
+		expected error
+		message  string
+	}{
+		{
+			name:     "cycle",
+			content:  "INTERP_A=${INTERP_B}\nINTERP_B=x${INTERP_C}\nINTERP_C=$INTERP_A\n",
+			expected: ErrReferenceCycle,
+			message:  "INTERP_A -> INTERP_B -> INTERP_C -> INTERP_A",
+		},
+		{
+			name:     "required",
+			content:  "INTERP_A=${INTERP_MISSING:?set it in .env.local}\n",
+			expected: ErrRequiredVar,
+			message: "INTERP_A (" + filepath.Join(suite.dir, ".env") + ":1): " + ErrRequiredVar.Error() +
+				": INTERP_MISSING: set it in .env.local",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			unsetEnv(suite.T(), "INTERP_A", "INTERP_B", "INTERP_C", "INTERP_MISSING")
+			writeEnvFile(suite.T(), suite.dir, ".env", testCase.content)
+
+			err := LoadEnvVars("test", suite.dir)
+
+			suite.Require().Error(err)
+			suite.Assert().True(errors.Is(err, testCase.expected))
+			suite.Assert().Contains(err.Error(), testCase.message)
+		})
+	}
+}
+
+func TestInterpolationSuite(t *testing.T) {
+	suite.Run(t, new(InterpolationTestSuite))
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// jsonSchemaDialect is the JSON Schema draft JSONSchema documents declare.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// JSONSchema returns a JSON Schema (draft 2020-12) document describing the JSON encoding of a config
+// struct tree. Properties are named like encoding/json names them and annotated with the `desc` and
+// `default` tags. The required, min, max, len, oneof, url and email validator rules are translated
+// to schema keywords; rules after "dive" and other rules are ignored.
+func JSONSchema(config interface{}) ([]byte, error) {
+	typ := reflect.TypeOf(config)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct or pointer to struct, got %T", config)
+	}
+
+	schema := typeSchema(typ, make(map[reflect.Type]bool))
+	schema["$schema"] = jsonSchemaDialect
+	schema["title"] = typ.Name()
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// typeSchema returns the schema of a Go type. Struct types already being described (recursive
+// types) are described by an empty schema.
+func typeSchema(typ reflect.Type, describing map[reflect.Type]bool) map[string]any {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Implements(jsonMarshalerType) || reflect.PointerTo(typ).Implements(jsonMarshalerType) {
+		return map[string]any{}
+	}
+
+	if typ.Implements(textMarshalerType) || reflect.PointerTo(typ).Implements(textMarshalerType) {
+		return map[string]any{"type": "string"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": typeSchema(typ.Elem(), describing)}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": typeSchema(typ.Elem(), describing),
+		}
+	case reflect.Struct:
+		if describing[typ] {
+			return map[string]any{}
+		}
+		describing[typ] = true
+		defer delete(describing, typ)
+		return structSchema(typ, describing)
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the object schema of a struct type.
+func structSchema(typ reflect.Type, describing map[reflect.Type]bool) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		schema := typeSchema(field.Type, describing)
+		if embedded, ok := schema["properties"].(map[string]any); ok && field.Anonymous &&
+			field.Tag.Get("json") == "" {
+			// encoding/json promotes the fields of embedded structs
+			for embeddedName, property := range embedded {
+				properties[embeddedName] = property
+			}
+			if embeddedRequired, ok := schema["required"].([]string); ok {
+				required = append(required, embeddedRequired...)
+			}
+			continue
+		}
+
+		if description := field.Tag.Get("desc"); description != "" {
+			schema["description"] = description
+		}
+		if value, ok := jsonSchemaValue(schema, field.Tag.Get("default")); ok {
+			schema["default"] = value
+		}
+
+		if applyValidateRules(schema, field.Tag.Get("validate")) {
+			required = append(required, name)
+		}
+		properties[name] = schema
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// jsonFieldName returns the name encoding/json uses for a field and whether the field is skipped.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	embeddedStruct := field.Anonymous && indirectType(field.Type).Kind() == reflect.Struct
+	if !field.IsExported() && !embeddedStruct {
+		return "", true
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", true
+	}
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, false
+}
+
+// applyValidateRules translates validator rules into keywords of a property schema and reports
+// whether the property is required.
+func applyValidateRules(schema map[string]any, validate string) bool {
+	required := false
+	for _, rule := range strings.Split(validate, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "dive":
+			return required
+		case "required":
+			required = true
+		case "url":
+			schema["format"] = "uri"
+		case "email":
+			schema["format"] = "email"
+		case "oneof":
+			var values []any
+			for _, option := range strings.Fields(param) {
+				if value, ok := jsonSchemaValue(schema, option); ok {
+					values = append(values, value)
+				}
+			}
+			schema["enum"] = values
+		case "min", "max", "len":
+			applyBoundRule(schema, name, param)
+		}
+	}
+
+	return required
+}
+
+// applyBoundRule translates a min, max or len rule, whose meaning depends on the property type.
+func applyBoundRule(schema map[string]any, rule string, param string) {
+	keywords := map[string][2]string{
+		"string":  {"minLength", "maxLength"},
+		"array":   {"minItems", "maxItems"},
+		"object":  {"minProperties", "maxProperties"},
+		"integer": {"minimum", "maximum"},
+		"number":  {"minimum", "maximum"},
+	}[fmt.Sprint(schema["type"])]
+
+	if keywords[0] == "" {
+		return
+	}
+
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	if rule != "max" {
+		schema[keywords[0]] = bound
+	}
+	if rule != "min" {
+		schema[keywords[1]] = bound
+	}
+}
+
+// jsonSchemaValue converts a tag value to the JSON type of a schema, reporting whether it fits.
+func jsonSchemaValue(schema map[string]any, value string) (any, bool) {
+	if value == "" {
+		return nil, false
+	}
+
+	switch schema["type"] {
+	case "string":
+		return value, true
+	case "integer":
+		number, err := strconv.ParseInt(value, 10, 64)
+		return number, err == nil
+	case "number":
+		number, err := strconv.ParseFloat(value, 64)
+		return number, err == nil
+	case "boolean":
+		boolean, err := strconv.ParseBool(value)
+		return boolean, err == nil
+	default:
+		return nil, false
+	}
+}
+
+// indirectType returns the type pointer types point to.
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JSONSchemaTestSuite struct {
+	suite.Suite
+}
+
+type schemaBase struct {
+	Region string `json:"region" validate:"required"`
+}
+
+type schemaNode struct {
+	Name     string        `json:"name"`
+	Children []*schemaNode `json:"children"`
+}
+
+type schemaAppConfig struct {
+	schemaBase
+	Port     int               `json:"port" default:"8080" desc:"Listen port" validate:"required,min=1,max=65535"`
+	Mode     string            `json:"mode" default:"dev" validate:"oneof=dev prod"`
+	Name     string            `validate:"min=3,max=20"`
+	Endpoint string            `json:"endpoint" validate:"url"`
+	Admin    string            `json:"admin,omitempty" validate:"email"`
+	Tags     []string          `json:"tags" validate:"min=1,dive,min=2"`
+	Labels   map[string]string `json:"labels"`
+	Ratio    float64           `json:"ratio" default:"0.5"`
+	Debug    bool              `json:"debug" default:"true"`
+	Timeout  time.Duration     `json:"timeout"`
+	Started  time.Time         `json:"started"`
+	Key      []byte            `json:"key"`
+	Tree     *schemaNode       `json:"tree"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func (suite *JSONSchemaTestSuite) schema(config interface{}) map[string]any {
+	document, err := JSONSchema(config)
+	suite.Require().NoError(err)
+
+	var schema map[string]any
+	suite.Require().NoError(json.Unmarshal(document, &schema))
+	return schema
+}
+
+func (suite *JSONSchemaTestSuite) TestItDescribesTheDocument() {
+	schema := suite.schema(&schemaAppConfig{})
+
+	suite.Assert().Equal("https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+	suite.Assert().Equal("schemaAppConfig", schema["title"])
+	suite.Assert().Equal("object", schema["type"])
+	suite.Assert().ElementsMatch([]any{"region", "port"}, schema["required"])
+}
+
+func (suite *JSONSchemaTestSuite) TestItNamesPropertiesLikeEncodingJSON() {
+	properties := suite.schema(schemaAppConfig{})["properties"].(map[string]any)
+
+	suite.Assert().Contains(properties, "region")
+	suite.Assert().Contains(properties, "Name")
+	suite.Assert().Contains(properties, "admin")
+	suite.Assert().NotContains(properties, "schemaBase")
+	suite.Assert().NotContains(properties, "Ignored")
+	suite.Assert().NotContains(properties, "internal")
+}
+
+func (suite *JSONSchemaTestSuite) TestItTranslatesTagsToKeywords() {
+	properties := suite.schema(&schemaAppConfig{})["properties"].(map[string]any)
+
+	testCases := []struct {
+		property string
+		expected map[string]any
+	}{
+		{"port", map[string]any{
+			"type": "integer", "default": 8080.0, "description": "Listen port",
+			"minimum": 1.0, "maximum": 65535.0,
+		}},
+		{"mode", map[string]any{"type": "string", "default": "dev", "enum": []any{"dev", "prod"}}},
+		{"Name", map[string]any{"type": "string", "minLength": 3.0, "maxLength": 20.0}},
+		{"endpoint", map[string]any{"type": "string", "format": "uri"}},
+		{"admin", map[string]any{"type": "string", "format": "email"}},
+		{"tags", map[string]any{
+			"type": "array", "items": map[string]any{"type": "string"}, "minItems": 1.0,
+		}},
+		{"labels", map[string]any{
+			"type": "object", "additionalProperties": map[string]any{"type": "string"},
+		}},
+		{"ratio", map[string]any{"type": "number", "default": 0.5}},
+		{"debug", map[string]any{"type": "boolean", "default": true}},
+		{"timeout", map[string]any{"type": "integer"}},
+		{"started", map[string]any{}},
+		{"key", map[string]any{"type": "string", "contentEncoding": "base64"}},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.property, func() {
+			suite.Assert().Equal(testCase.expected, properties[testCase.property])
+		})
+	}
+}
+
+func (suite *JSONSchemaTestSuite) TestItDescribesRecursiveTypes() {
+	properties := suite.schema(&schemaAppConfig{})["properties"].(map[string]any)
+
+	tree := properties["tree"].(map[string]any)
+	children := tree["properties"].(map[string]any)["children"].(map[string]any)
+
+	suite.Assert().Equal(map[string]any{"type": "array", "items": map[string]any{}}, children)
+}
+
+func (suite *JSONSchemaTestSuite) TestItFailsForNonStructs() {
+	_, err := JSONSchema("config")
+
+	suite.Require().Error(err)
+	suite.Assert().Equal("expected struct or pointer to struct, got string", err.Error())
+}
+
+func TestJSONSchemaSuite(t *testing.T) {
+	suite.Run(t, new(JSONSchemaTestSuite))
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"io/fs"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Defaults of the environment name and base directory used by Load.
+const (
+	DefaultEnv = "dev"
+	DefaultDir = "."
+)
+
+// WithEnv sets the environment name loaded by Load, and by PopulateAndValidate when its defaultEnv
+// argument is empty. Defaults to DefaultEnv. WithEnvDetection takes priority when its variable is
+// set.
+func WithEnv(env string) Option {
+	return func(c *CompositeConfig) {
+		c.defaultEnv = env
+	}
+}
+
+// WithDir sets the base directory of the env files loaded by Load, and by PopulateAndValidate when
+// its defaultAppDir argument is empty. Defaults to DefaultDir.
+func WithDir(dir string) Option {
+	return func(c *CompositeConfig) {
+		c.baseDir = dir
+	}
+}
+
+// WithDirs adds directories searched for env files after the base directory and the Loader.Dirs,
+// in priority order.
+func WithDirs(dirs ...string) Option {
+	return func(c *CompositeConfig) {
+		c.extraDirs = append(c.extraDirs, dirs...)
+	}
+}
+
+// WithSources adds file systems (e.g. an embed.FS) searched for env files after the directories
+// and the Loader.Sources, in priority order.
+func WithSources(sources ...fs.FS) Option {
+	return func(c *CompositeConfig) {
+		c.sources = append(c.sources, sources...)
+	}
+}
+
+// WithValidator sets the validator of the config struct, like passing it to NewCompositeConfig.
+func WithValidator(customValidator *validator.Validate) Option {
+	return func(c *CompositeConfig) {
+		if customValidator != nil {
+			c.validator = customValidator
+		}
+	}
+}
+
+// LoadResult receives the details of a Load or MustLoad call, see WithResult.
+type LoadResult struct {
+	// Env is the environment name used, e.g. as detected by WithEnvDetection, so application code
+	// can branch on it.
+	Env string
+	// Report lists the loaded env files and the file values shadowed by the process environment.
+	Report *LoadReport
+}
+
+// WithResult makes Load and MustLoad fill the result with the environment name and the load
+// report, which are otherwise only available from a CompositeConfig (Env and LoadReport). The
+// result is filled even when loading fails, as far as it got.
+func WithResult(result *LoadResult) Option {
+	return func(c *CompositeConfig) {
+		c.result = result
+	}
+}
+
+// Load allocates a T, populates it like PopulateAndValidate and validates it, returning the fully
+// populated value:
+//
+//	appConfig, err := config.Load[AppConfig](config.WithEnv("prod"), config.WithDir("./config"))
+func Load[T any](opts ...Option) (*T, error) {
+	return load[T](NewCompositeConfig(nil, opts...))
+}
+
+// load allocates a T and populates and validates it with a CompositeConfig.
+func load[T any](c *CompositeConfig) (*T, error) {
+	config := new(T)
+	err := c.PopulateAndValidate(config, c.defaultEnv, c.baseDir)
+	if c.result != nil {
+		*c.result = LoadResult{Env: c.Env(), Report: c.LoadReport()}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// envLoader returns the Loader extended with the directories and sources set by the options.
+func (c *CompositeConfig) envLoader() *Loader {
+	if len(c.extraDirs) == 0 && len(c.sources) == 0 {
+		return c.loader
+	}
+
+	loader := *c.loader
+	loader.Dirs = append(append([]string(nil), c.loader.Dirs...), c.extraDirs...)
+	loader.Sources = append(append([]fs.FS(nil), c.loader.Sources...), c.sources...)
+	return &loader
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoadTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+type loadServerConfig struct {
+	Host string `env:"LOAD_HOST" validate:"required"`
+	Mode string `env:"LOAD_MODE" validate:"omitempty,mode"`
+}
+
+func (s *loadServerConfig) Populate() error {
+	s.Host = os.Getenv("LOAD_HOST")
+	s.Mode = os.Getenv("LOAD_MODE")
+	return nil
+}
+
+type loadAppConfig struct {
+	Server loadServerConfig
+}
+
+func (suite *LoadTestSuite) SetupTest() {
+	unsetEnv(suite.T(), "LOAD_HOST", "LOAD_MODE")
+
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *LoadTestSuite) modeValidator() *validator.Validate {
+	customValidator := validator.New()
+	isMode := func(fl validator.FieldLevel) bool {
+		return fl.Field().String() == "fast" || fl.Field().String() == "embedded"
+	}
+	suite.Require().NoError(customValidator.RegisterValidation("mode", isMode))
+	return customValidator
+}
+
+func (suite *LoadTestSuite) TestItLoadsATypedConfig() {
+	writeEnvFile(suite.T(), suite.dir, ".env.prod", "LOAD_HOST=prod.local\n")
+	writeEnvFile(suite.T(), suite.dir, ".env", "LOAD_HOST=localhost\nLOAD_MODE=fast\n")
+
+	appConfig, err := Load[loadAppConfig](
+		WithEnv("prod"),
+		WithDir(suite.dir),
+		WithValidator(suite.modeValidator()),
+	)
+
+	suite.Require().NoError(err)
+	expected := &loadAppConfig{Server: loadServerConfig{Host: "prod.local", Mode: "fast"}}
+	suite.Assert().Equal(expected, appConfig)
+}
+
+func (suite *LoadTestSuite) TestItExposesTheEnvAndLoadReport() {
+	writeEnvFile(suite.T(), suite.dir, ".env.prod", "LOAD_HOST=prod.local\n")
+	suite.T().Setenv("LOAD_MODE", "embedded")
+	writeEnvFile(suite.T(), suite.dir, ".env", "LOAD_MODE=fast\n")
+	var result LoadResult
+
+	_, err := Load[loadAppConfig](
+		WithEnv("prod"),
+		WithDir(suite.dir),
+		WithValidator(suite.modeValidator()),
+		WithResult(&result),
+	)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal("prod", result.Env)
+	suite.Require().NotNil(result.Report)
+	suite.Assert().Len(result.Report.Files, 2)
+	suite.Require().Len(result.Report.Shadowed, 1)
+	suite.Assert().Equal("LOAD_MODE", result.Report.Shadowed[0].Key)
+}
+
+func (suite *LoadTestSuite) TestItSearchesAdditionalDirsAndSources() {
+	sharedDir := suite.T().TempDir()
+	writeEnvFile(suite.T(), sharedDir, ".env", "LOAD_HOST=shared.local\n")
+	sources := fstest.MapFS{".env.dev": {Data: []byte("LOAD_HOST=embedded\nLOAD_MODE=embedded\n")}}
+	loader := NewLoader()
+
+	appConfig, err := Load[loadAppConfig](
+		WithLoader(loader),
+		WithDir(suite.dir),
+		WithDirs(sharedDir),
+		WithSources(sources),
+		WithValidator(suite.modeValidator()),
+	)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal("shared.local", appConfig.Server.Host)
+	suite.Assert().Equal("embedded", appConfig.Server.Mode)
+	suite.Assert().Empty(loader.Dirs, "the loader is not modified")
+	suite.Assert().Empty(loader.Sources, "the loader is not modified")
+}
+
+func (suite *LoadTestSuite) TestItFailsWithoutReturningAnInvalidConfig() {
+	writeEnvFile(suite.T(), suite.dir, ".env", "LOAD_HOST=localhost\nLOAD_MODE=slow\n")
+
+	appConfig, err := Load[loadAppConfig](WithDir(suite.dir), WithValidator(suite.modeValidator()))
+
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "config validation failed")
+	suite.Assert().Nil(appConfig)
+}
+
+func (suite *LoadTestSuite) TestItFallsBackToTheEnvAndDirOptions() {
+	writeEnvFile(suite.T(), suite.dir, ".env.staging", "LOAD_HOST=staging.local\n")
+	compositeConfig := NewCompositeConfig(
+		suite.modeValidator(),
+		WithEnv("staging"),
+		WithDir(suite.dir),
+	)
+	appConfig := &loadAppConfig{}
+
+	err := compositeConfig.PopulateAndValidate(appConfig, "", "")
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal("staging.local", appConfig.Server.Host)
+}
+
+func (suite *LoadTestSuite) TestItRejectsNonStructTypes() {
+	_, err := Load[string](WithDir(suite.dir))
+
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "expected struct or pointer to struct, got *string")
+}
+
+func TestLoadSuite(t *testing.T) {
+	suite.Run(t, new(LoadTestSuite))
+}
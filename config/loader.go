@@ -0,0 +1,352 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// envPlaceholder is replaced by the environment name in Loader file patterns.
+const envPlaceholder = "{env}"
+
+// ErrMissingEnvFile is returned when a required env file does not exist.
+var ErrMissingEnvFile = errors.New("required env file not found")
+
+// defaultStopMarkers are the file names marking the top directory of the upward search.
+var defaultStopMarkers = []string{"go.mod", ".git"}
+
+// Loader loads a cascade of env files and sets their entries as env variables for this process.
+// The zero value loads nothing; NewLoader returns the default cascade used by LoadEnvVars.
+type Loader struct {
+	// Files lists the env file name patterns in priority order, highest first.
+	// The "{env}" placeholder is replaced by the environment name.
+	Files []string
+	// Skip maps environment names to the file patterns not loaded for them.
+	Skip map[string][]string
+	// Required lists the file patterns that must exist in at least one of the searched directories.
+	Required []string
+	// Dirs lists additional directories searched after the base directory, in priority order.
+	// All the files found in a directory take priority over the files of the next ones.
+	Dirs []string
+	// SearchUp makes Load walk up from the base directory and load the cascade from the first
+	// directory containing one of its files, e.g. to find the module's env files from the test of a
+	// sub-package. The search stops at a directory containing one of the StopMarkers or at StopDir.
+	SearchUp bool
+	// StopMarkers lists the file names marking the top directory of the upward search.
+	// Defaults to go.mod and .git.
+	StopMarkers []string
+	// StopDir is the last directory checked by the upward search, if set.
+	StopDir string
+	// Sources lists file systems (e.g. an embed.FS with default env files) searched for the cascade
+	// after the directories, in priority order, so on-disk files take priority over them.
+	// Their files are reported with an "fs:" prefix, e.g. "fs:.env".
+	Sources []fs.FS
+	// FSOnly disables loading files from disk so only Sources are loaded, e.g. for hermetic tests.
+	FSOnly bool
+	// Override makes file values overwrite the values already set in the process environment,
+	// e.g. for test fixtures or when a stale exported shell variable shadows .env.local.
+	Override bool
+}
+
+// LoadReport describes what a Loader loaded.
+type LoadReport struct {
+	// Files lists the loaded env files in priority order.
+	Files []string
+	// Keys lists the origins of all the keys declared in the loaded files, including the ones whose
+	// value was not used.
+	Keys []Origin
+	// Shadowed lists the file declarations not applied because their key was already set in the
+	// process environment by another source. It is always empty in override mode.
+	Shadowed []Origin
+}
+
+// NewLoader creates a Loader with the default cascade: .env.{env}.local, .env.local (skipped for
+// the test environment), .env.{env} and .env. Missing files are skipped.
+func NewLoader() *Loader {
+	return &Loader{
+		Files: []string{
+			".env." + envPlaceholder + ".local",
+			".env.local",
+			".env." + envPlaceholder,
+			".env",
+		},
+		Skip: map[string][]string{"test": {".env.local"}},
+	}
+}
+
+// EnvValue is the effective value of an env var and its origin.
+type EnvValue struct {
+	Value  string
+	Origin Origin
+}
+
+// Load loads the env files of the cascade for an environment, searching the base directory, the
+// additional directories and then the Sources. Like LoadEnvVars, it never overwrites existing env
+// vars, unless Override is set.
+func (l *Loader) Load(env string, appBaseDir string) (*LoadReport, error) {
+	set, err := l.collect(env, appBaseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	shadowed, err := applyEnvFiles(set.files, l.Override)
+	if err != nil {
+		return nil, err
+	}
+	set.report.Shadowed = shadowed
+
+	return set.report, nil
+}
+
+// Resolve returns the effective values of the keys declared in the env files of the cascade, like
+// Load would set them, without modifying the process environment. Keys already set in the process
+// environment keep their value, unless Override is set.
+func (l *Loader) Resolve(env string, appBaseDir string) (map[string]EnvValue, *LoadReport, error) {
+	set, err := l.collect(env, appBaseDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolver := newFileResolver(set.files, l.Override)
+	values, err := resolver.resolveAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve env file values: %w", err)
+	}
+
+	resolved := make(map[string]EnvValue, len(resolver.keys))
+	for _, key := range resolver.keys {
+		fileOrigin := resolver.entries[key].origin()
+		if value, ok := values[key]; ok {
+			resolved[key] = EnvValue{Value: value, Origin: fileOrigin}
+			continue
+		}
+
+		origin := OriginOf(key)
+		if origin != fileOrigin {
+			set.report.Shadowed = append(set.report.Shadowed, fileOrigin)
+		}
+		resolved[key] = EnvValue{Value: os.Getenv(key), Origin: origin}
+	}
+
+	return resolved, set.report, nil
+}
+
+// collect finds and parses the env files of the cascade for an environment.
+func (l *Loader) collect(env string, appBaseDir string) (*envFileSet, error) {
+	if l.SearchUp {
+		dir, err := l.searchUp(env, appBaseDir)
+		if err != nil {
+			return nil, err
+		}
+		appBaseDir = dir
+	}
+
+	set := &envFileSet{report: &LoadReport{}, found: make(map[string]bool)}
+	if !l.FSOnly {
+		for _, dir := range append([]string{appBaseDir}, l.Dirs...) {
+			for _, pattern := range l.patterns(env) {
+				fileName := filepath.Join(dir, strings.ReplaceAll(pattern, envPlaceholder, env))
+				if !fileExists(fileName) {
+					continue
+				}
+
+				content, err := os.ReadFile(fileName)
+				if err == nil {
+					err = set.add(pattern, fileName, content)
+				}
+				if err != nil {
+					return nil, formatEnvLoadErr(fileName, err)
+				}
+			}
+		}
+	}
+
+	for _, fsys := range l.Sources {
+		for _, pattern := range l.patterns(env) {
+			name := strings.ReplaceAll(pattern, envPlaceholder, env)
+			content, err := fs.ReadFile(fsys, name)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err == nil {
+				err = set.add(pattern, "fs:"+name, content)
+			}
+			if err != nil {
+				return nil, formatEnvLoadErr("fs:"+name, err)
+			}
+		}
+	}
+
+	for _, pattern := range l.patterns(env) {
+		if slices.Contains(l.Required, pattern) && !set.found[pattern] {
+			return nil, fmt.Errorf(
+				"%w: %s",
+				ErrMissingEnvFile,
+				strings.ReplaceAll(pattern, envPlaceholder, env),
+			)
+		}
+	}
+
+	return set, nil
+}
+
+// envFileSet accumulates the parsed env files of a cascade in priority order.
+type envFileSet struct {
+	report *LoadReport
+	found  map[string]bool
+	files  [][]dotenvEntry
+}
+
+// add parses the content of an env file matching a pattern and adds it with the lowest priority.
+func (s *envFileSet) add(pattern string, fileName string, content []byte) error {
+	entries, err := parseDotenv(content)
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		entries[i].File = fileName
+		s.report.Keys = append(s.report.Keys, entries[i].origin())
+	}
+
+	s.found[pattern] = true
+	s.files = append(s.files, entries)
+	s.report.Files = append(s.report.Files, fileName)
+	return nil
+}
+
+// searchUp returns the first directory, from appBaseDir upward, containing a file of the cascade.
+// It returns appBaseDir when no directory up to the stop one contains such a file.
+func (l *Loader) searchUp(env string, appBaseDir string) (string, error) {
+	dir, err := filepath.Abs(appBaseDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve env files dir %s: %w", appBaseDir, err)
+	}
+
+	stopDir := ""
+	if l.StopDir != "" {
+		if stopDir, err = filepath.Abs(l.StopDir); err != nil {
+			return "", fmt.Errorf("failed to resolve stop dir %s: %w", l.StopDir, err)
+		}
+	}
+
+	stopMarkers := l.StopMarkers
+	if stopMarkers == nil {
+		stopMarkers = defaultStopMarkers
+	}
+
+	for {
+		for _, pattern := range l.patterns(env) {
+			if fileExists(filepath.Join(dir, strings.ReplaceAll(pattern, envPlaceholder, env))) {
+				return dir, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if dir == stopDir || parent == dir || containsAny(dir, stopMarkers) {
+			return appBaseDir, nil
+		}
+		dir = parent
+	}
+}
+
+// containsAny reports whether a directory contains at least one of the given file names.
+func containsAny(dir string, names []string) bool {
+	for _, name := range names {
+		if fileExists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fileExists reports whether a file or directory exists.
+func fileExists(fileName string) bool {
+	_, err := os.Stat(fileName)
+	return err == nil
+}
+
+// patterns returns the file patterns loaded for an environment, in priority order.
+func (l *Loader) patterns(env string) []string {
+	patterns := make([]string, 0, len(l.Files))
+	for _, pattern := range l.Files {
+		if !slices.Contains(l.Skip[env], pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
+
+// LoadEnvVars Loads the entries from env files and sets them as env variables for this process.
+// Loads each file in order: .env.{dev|prod|test}.local, .env.local, .env.{dev|prod|test}, .env.
+// The first loaded file has priority. Files will not overwrite the values of the already loaded
+// env vars (already loaded from env files or via other means).
+// Values can reference other vars as $VAR, ${VAR}, ${VAR:-default} or ${VAR:?error message}.
+// References are resolved across all the loaded files using the same priority order, and
+// single-quoted values are taken literally.
+// The origin (file and line) of every value it sets is recorded and available via OriginOf.
+// Use a Loader to customize the cascade.
+func LoadEnvVars(env string, appBaseDir string) error {
+	_, err := NewLoader().Load(env, appBaseDir)
+	return err
+}
+
+// applyEnvFiles interpolates the entries of env files, given in priority order, and sets them as env
+// vars, recording their origin. Unless override is set, keys already set in the process environment
+// are kept and the file declarations shadowed by another source are returned.
+func applyEnvFiles(files [][]dotenvEntry, override bool) ([]Origin, error) {
+	resolver := newFileResolver(files, override)
+	values, err := resolver.resolveAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve env file values: %w", err)
+	}
+
+	var shadowed []Origin
+	for _, key := range resolver.keys {
+		fileOrigin := resolver.entries[key].origin()
+		value, resolved := values[key]
+		if !resolved {
+			if OriginOf(key) != fileOrigin {
+				shadowed = append(shadowed, fileOrigin)
+			}
+			continue
+		}
+
+		if err := os.Setenv(key, value); err != nil {
+			return nil, err
+		}
+		recordOrigin(fileOrigin, value)
+	}
+
+	return shadowed, nil
+}
+
+// newFileResolver creates a resolver of env files looking up the process environment. In override
+// mode, the process values of the keys declared in the files are ignored.
+func newFileResolver(files [][]dotenvEntry, override bool) *envResolver {
+	resolver := newEnvResolver(files, os.LookupEnv)
+	if override {
+		resolver.lookupEnv = func(key string) (string, bool) {
+			if _, declared := resolver.entries[key]; declared {
+				return "", false
+			}
+			return os.LookupEnv(key)
+		}
+	}
+
+	return resolver
+}
+
+func formatEnvLoadErr(fileName string, err error) error {
+	return fmt.Errorf(
+		"error occurred while trying to load env file: %s. Error message: %s",
+		fileName,
+		err.Error(),
+	)
+}
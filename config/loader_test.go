@@ -0,0 +1,259 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// writeEnvFile writes an env file in a directory and returns its path.
+func writeEnvFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	fileName := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(fileName, []byte(content), 0644))
+	return fileName
+}
+
+// unsetEnv removes env vars for the duration of the test, restoring them afterward.
+func unsetEnv(t *testing.T, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		t.Setenv(key, "")
+		_ = os.Unsetenv(key)
+	}
+}
+
+type LoaderTestSuite struct {
+	suite.Suite
+	dir       string
+	sharedDir string
+}
+
+func (suite *LoaderTestSuite) SetupTest() {
+	unsetEnv(suite.T(), "LOADER_MODE", "LOADER_SHARED", "LOADER_LOCAL")
+
+	suite.dir = suite.T().TempDir()
+	suite.sharedDir = suite.T().TempDir()
+}
+
+func (suite *LoaderTestSuite) TestItLoadsTheDefaultCascade() {
+	writeEnvFile(suite.T(), suite.dir, ".env.local", "LOADER_LOCAL=local\n")
+	envFile := writeEnvFile(suite.T(), suite.dir, ".env.dev", "LOADER_MODE=dev\n")
+	baseFile := writeEnvFile(suite.T(), suite.dir, ".env", "LOADER_MODE=base\n")
+
+	report, err := NewLoader().Load("dev", suite.dir)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]string{filepath.Join(suite.dir, ".env.local"), envFile, baseFile}, report.Files)
+	suite.Assert().Equal("dev", os.Getenv("LOADER_MODE"))
+	suite.Assert().Equal("local", os.Getenv("LOADER_LOCAL"))
+}
+
+func (suite *LoaderTestSuite) TestItLoadsCustomPatternsAndSkipsFilesPerEnvironment() {
+	writeEnvFile(suite.T(), suite.dir, "app.ci.env", "LOADER_MODE=ci\n")
+	writeEnvFile(suite.T(), suite.dir, "app.local.env", "LOADER_LOCAL=local\n")
+	loader := &Loader{
+		Files: []string{"app.local.env", "app.{env}.env"},
+		Skip:  map[string][]string{"ci": {"app.local.env"}},
+	}
+
+	report, err := loader.Load("ci", suite.dir)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]string{filepath.Join(suite.dir, "app.ci.env")}, report.Files)
+	suite.Assert().Equal("ci", os.Getenv("LOADER_MODE"))
+	suite.Assert().Empty(os.Getenv("LOADER_LOCAL"))
+}
+
+func (suite *LoaderTestSuite) TestItSearchesAdditionalDirsWithLowerPriority() {
+	writeEnvFile(suite.T(), suite.dir, ".env", "LOADER_MODE=app\n")
+	writeEnvFile(
+		suite.T(),
+		suite.sharedDir,
+		".env.dev.local",
+		"LOADER_MODE=shared\nLOADER_SHARED=shared\n",
+	)
+	loader := NewLoader()
+	loader.Dirs = []string{suite.sharedDir}
+
+	report, err := loader.Load("dev", suite.dir)
+
+	suite.Require().NoError(err)
+	suite.Assert().Len(report.Files, 2)
+	suite.Assert().Equal("app", os.Getenv("LOADER_MODE"))
+	suite.Assert().Equal("shared", os.Getenv("LOADER_SHARED"))
+}
+
+func (suite *LoaderTestSuite) TestItFailsWhenRequiredFilesAreMissing() {
+	writeEnvFile(suite.T(), suite.dir, ".env", "LOADER_MODE=base\n")
+	loader := NewLoader()
+	loader.Required = []string{".env", ".env.{env}"}
+
+	_, err := loader.Load("prod", suite.dir)
+
+	suite.Assert().True(errors.Is(err, ErrMissingEnvFile))
+	suite.Assert().Contains(err.Error(), ".env.prod")
+	suite.Assert().Empty(os.Getenv("LOADER_MODE"))
+}
+
+func (suite *LoaderTestSuite) TestItDoesNotRequireSkippedFiles() {
+	loader := NewLoader()
+	loader.Required = []string{".env.local"}
+
+	_, err := loader.Load("test", suite.dir)
+
+	suite.Assert().NoError(err)
+}
+
+func (suite *LoaderTestSuite) TestItSearchesUpForTheFirstDirWithEnvFiles() {
+	subDir := filepath.Join(suite.dir, "internal", "pkg")
+	suite.Require().NoError(os.MkdirAll(subDir, 0755))
+	writeEnvFile(suite.T(), suite.dir, "go.mod", "module example\n")
+	envFile := writeEnvFile(suite.T(), suite.dir, ".env.test", "LOADER_MODE=root\n")
+	loader := NewLoader()
+	loader.SearchUp = true
+
+	report, err := loader.Load("test", subDir)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]string{envFile}, report.Files)
+	suite.Assert().Equal("root", os.Getenv("LOADER_MODE"))
+}
+
+func (suite *LoaderTestSuite) TestItStopsSearchingUpAtMarkersAndStopDir() {
+	moduleDir := filepath.Join(suite.dir, "module")
+	subDir := filepath.Join(moduleDir, "pkg")
+	suite.Require().NoError(os.MkdirAll(subDir, 0755))
+	writeEnvFile(suite.T(), suite.dir, ".env", "LOADER_MODE=outside\n")
+	writeEnvFile(suite.T(), moduleDir, "marker", "")
+
+	testCases := []struct {
+		name   string
+		loader *Loader
+	}{
+		{"marker", &Loader{Files: []string{".env"}, SearchUp: true, StopMarkers: []string{"marker"}}},
+		{"stop dir", &Loader{Files: []string{".env"}, SearchUp: true, StopDir: moduleDir}},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			report, err := testCase.loader.Load("dev", subDir)
+
+			suite.Require().NoError(err)
+			suite.Assert().Empty(report.Files)
+			suite.Assert().Empty(os.Getenv("LOADER_MODE"))
+		})
+	}
+}
+
+func (suite *LoaderTestSuite) TestItLoadsSourcesBeneathOnDiskFiles() {
+	writeEnvFile(suite.T(), suite.dir, ".env", "LOADER_MODE=disk\n")
+	loader := NewLoader()
+	loader.Required = []string{".env.{env}"}
+	loader.Sources = []fs.FS{fstest.MapFS{
+		".env.prod": {Data: []byte("LOADER_MODE=embedded\nLOADER_SHARED=embedded\n")},
+	}}
+
+	report, err := loader.Load("prod", suite.dir)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]string{filepath.Join(suite.dir, ".env"), "fs:.env.prod"}, report.Files)
+	suite.Assert().Equal("disk", os.Getenv("LOADER_MODE"))
+	suite.Assert().Equal("embedded", os.Getenv("LOADER_SHARED"))
+	suite.Assert().Equal(
+		Origin{Key: "LOADER_SHARED", Source: SourceFile, File: "fs:.env.prod", Line: 2},
+		OriginOf("LOADER_SHARED"),
+	)
+}
+
+func (suite *LoaderTestSuite) TestItCanLoadOnlySources() {
+	writeEnvFile(suite.T(), suite.dir, ".env", "LOADER_MODE=disk\n")
+	loader := &Loader{
+		Files:   []string{".env"},
+		Sources: []fs.FS{fstest.MapFS{".env": {Data: []byte("LOADER_MODE=embedded\n")}}},
+		FSOnly:  true,
+	}
+
+	_, err := loader.Load("test", suite.dir)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal("embedded", os.Getenv("LOADER_MODE"))
+}
+
+func (suite *LoaderTestSuite) TestItReportsFileValuesShadowedByTheProcessEnv() {
+	suite.T().Setenv("LOADER_MODE", "exported")
+	envFile := writeEnvFile(suite.T(), suite.dir, ".env", "LOADER_LOCAL=local\nLOADER_MODE=file\n")
+	compositeConfig := NewCompositeConfig(nil)
+
+	suite.Require().NoError(compositeConfig.PopulateAndValidate(&struct{}{}, "test", suite.dir))
+	report, err := NewLoader().Load("test", suite.dir)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal("exported", os.Getenv("LOADER_MODE"))
+	shadowed := []Origin{{Key: "LOADER_MODE", Source: SourceFile, File: envFile, Line: 2}}
+	suite.Assert().Equal(shadowed, compositeConfig.LoadReport().Shadowed)
+	suite.Assert().Equal(shadowed, report.Shadowed, "values loaded from the same file are not shadowed")
+}
+
+func (suite *LoaderTestSuite) TestItCanOverrideTheProcessEnv() {
+	suite.T().Setenv("LOADER_MODE", "exported")
+	writeEnvFile(
+		suite.T(),
+		suite.dir,
+		".env",
+		"LOADER_MODE=file\nLOADER_LOCAL=${LOADER_MODE}-local\n",
+	)
+	loader := NewLoader()
+	loader.Override = true
+
+	report, err := loader.Load("test", suite.dir)
+
+	suite.Require().NoError(err)
+	suite.Assert().Empty(report.Shadowed)
+	suite.Assert().Equal("file", os.Getenv("LOADER_MODE"))
+	suite.Assert().Equal("file-local", os.Getenv("LOADER_LOCAL"))
+	suite.Assert().Equal(SourceFile, OriginOf("LOADER_MODE").Source)
+}
+
+func (suite *LoaderTestSuite) TestItResolvesValuesWithoutSettingThem() {
+	suite.T().Setenv("LOADER_MODE", "exported")
+	envFile := writeEnvFile(
+		suite.T(),
+		suite.dir,
+		".env",
+		"LOADER_MODE=file\nLOADER_LOCAL=${LOADER_MODE}-local\n",
+	)
+
+	values, report, err := NewLoader().Resolve("test", suite.dir)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(
+		map[string]EnvValue{
+			"LOADER_MODE": {
+				Value:  "exported",
+				Origin: Origin{Key: "LOADER_MODE", Source: SourceProcessEnv},
+			},
+			"LOADER_LOCAL": {
+				Value:  "exported-local",
+				Origin: Origin{Key: "LOADER_LOCAL", Source: SourceFile, File: envFile, Line: 2},
+			},
+		},
+		values,
+	)
+	suite.Assert().Equal(
+		[]Origin{{Key: "LOADER_MODE", Source: SourceFile, File: envFile, Line: 1}},
+		report.Shadowed,
+	)
+	_, isSet := os.LookupEnv("LOADER_LOCAL")
+	suite.Assert().False(isSet)
+}
+
+func TestLoaderSuite(t *testing.T) {
+	suite.Run(t, new(LoaderTestSuite))
+}
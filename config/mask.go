@@ -0,0 +1,131 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MaskFunc masks a sensitive value for Debug. DSN values are masked component by component, so
+// a MaskFunc only receives their passwords and sensitive parameters.
+type MaskFunc func(value string) string
+
+// fixedMask is the output of MaskFixed.
+const fixedMask = "****"
+
+// maskStrategies maps the names accepted by the `mask` tag to the masking strategies.
+var maskStrategies = map[string]MaskFunc{
+	"partial": MaskPartial,
+	"full":    MaskFull,
+	"fixed":   MaskFixed,
+	"last4":   MaskLast4,
+	"sha256":  MaskFingerprint,
+}
+
+// MaskPartial shows the first and last character of a value with asterisks in between, e.g.
+// "s****t". It is the default strategy of Debug.
+func MaskPartial(value string) string {
+	return maskPartial(value)
+}
+
+// MaskFull redacts the whole value, e.g. "[REDACTED]", without revealing anything about it.
+func MaskFull(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return "[REDACTED]"
+}
+
+// MaskFixed replaces the value with a fixed-width "****", hiding its length.
+func MaskFixed(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return fixedMask
+}
+
+// MaskLast4 shows the last 4 characters of a value, e.g. "****1234" to identify a card or a key.
+// Values shorter than 8 characters are masked with MaskFixed, as they would be mostly revealed.
+func MaskLast4(value string) string {
+	if len(value) < 8 {
+		return MaskFixed(value)
+	}
+
+	return fixedMask + value[len(value)-4:]
+}
+
+// MaskFingerprint replaces the value with the prefix of its SHA-256 hash, e.g. "sha256:2bb80d53",
+// so secrets can be compared across hosts without being revealed.
+func MaskFingerprint(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:4])
+}
+
+// ParseMask returns the masking strategy of a name accepted by the `mask` tag: partial, full,
+// fixed, last4 or sha256.
+func ParseMask(name string) (MaskFunc, error) {
+	mask, ok := maskStrategies[strings.TrimSpace(name)]
+	if !ok {
+		return nil, fmt.Errorf(
+			"unknown mask %q, expected partial, full, fixed, last4 or sha256",
+			name,
+		)
+	}
+
+	return mask, nil
+}
+
+// maskValue masks a sensitive value with a strategy, component by component for DSNs.
+func maskValue(value string, mask MaskFunc) string {
+	if masked, ok := maskDSN(value, mask); ok {
+		return masked
+	}
+
+	return mask(value)
+}
+
+// checkMaskTags returns a *FieldError for every `mask` tag of a config struct tree naming no
+// masking strategy, e.g. `mask:"last-4"`. The struct types being checked are skipped, so
+// self-referencing types are checked once.
+func checkMaskTags(typ reflect.Type, path string, checking map[reflect.Type]bool) []error {
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice ||
+		typ.Kind() == reflect.Array || typ.Kind() == reflect.Map) {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct || checking[typ] {
+		return nil
+	}
+	checking[typ] = true
+	defer delete(checking, typ)
+
+	var errs []error
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		if name, ok := field.Tag.Lookup("mask"); ok {
+			if _, err := ParseMask(name); err != nil {
+				errs = append(errs, &FieldError{Field: fieldPath, Err: err})
+			}
+		}
+		errs = append(errs, checkMaskTags(field.Type, fieldPath, checking)...)
+	}
+
+	return errs
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MaskTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MaskTestSuite) TestItMasksValuesWithEachStrategy() {
+	testCases := []struct {
+		name     string
+		mask     MaskFunc
+		value    string
+		expected string
+	}{
+		{"partial", MaskPartial, "secret", "s****t"},
+		{"partial short", MaskPartial, "ab", "**"},
+		{"full", MaskFull, "secret", "[REDACTED]"},
+		{"fixed", MaskFixed, "a-very-long-secret", "****"},
+		{"last4", MaskLast4, "4111111111111234", "****1234"},
+		{"last4 short", MaskLast4, "secret", "****"},
+		{"sha256", MaskFingerprint, "secret", "sha256:2bb80d53"},
+		{"empty", MaskFixed, "", ""},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.Assert().Equal(testCase.expected, testCase.mask(testCase.value))
+		})
+	}
+}
+
+func (suite *MaskTestSuite) TestItParsesMaskNames() {
+	for _, name := range []string{"partial", "full", "fixed", "last4", "sha256"} {
+		mask, err := ParseMask(name)
+
+		suite.Require().NoError(err)
+		suite.Assert().NotNil(mask)
+	}
+
+	_, err := ParseMask("md5")
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), `unknown mask "md5"`)
+}
+
+func (suite *MaskTestSuite) TestItSelectsTheMaskPerDebugCall() {
+	config := struct {
+		Password    string
+		DatabaseDSN string
+		Settings    map[string]string
+	}{
+		Password:    "secret",
+		DatabaseDSN: "postgres://app:hunter2@db/app",
+		Settings:    map[string]string{"token": "secret"},
+	}
+
+	result := Debug(config, []string{"pass", "dsn", "token"}, WithMask(MaskFingerprint))
+
+	suite.Assert().Equal(
+		"Config Debug Output:\n"+
+			"Password: sha256:2bb80d53\n"+
+			"DatabaseDSN: postgres://app:sha256:f52fbd32@db/app\n"+
+			"Settings: \n"+
+			"  token: sha256:2bb80d53\n",
+		result,
+	)
+}
+
+func (suite *MaskTestSuite) TestItSelectsTheMaskPerFieldTag() {
+	config := struct {
+		Password string
+		CardPAN  string `mask:"last4"`
+		APIKey   string `mask:"full"`
+	}{
+		Password: "secret",
+		CardPAN:  "4111111111111234",
+		APIKey:   "api-key",
+	}
+
+	result := Debug(config, []string{"pass"}, WithMask(MaskFixed))
+
+	suite.Assert().Equal(
+		"Config Debug Output:\n"+
+			"Password: ****\n"+
+			"CardPAN: ****1234\n"+
+			"APIKey: [REDACTED]\n",
+		result,
+	)
+}
+
+type maskTypoConfig struct {
+	Token    string `mask:"last-4"`
+	Payments []struct {
+		CardPAN string `mask:"last4"`
+		CVV     string `mask:"hidden"`
+	}
+}
+
+func (suite *MaskTestSuite) TestItMasksFullyWithUnknownMaskTags() {
+	config := maskTypoConfig{Token: "token-1234"}
+
+	suite.Assert().Equal(
+		"Config Debug Output:\nToken: [REDACTED]\nPayments: \n  []\n",
+		Debug(config, nil),
+	)
+}
+
+func (suite *MaskTestSuite) TestItReportsUnknownMaskTagsWhenLoading() {
+	_, err := Load[maskTypoConfig](WithDir(suite.T().TempDir()))
+
+	suite.Require().Error(err)
+	suite.Assert().Equal(
+		[]Problem{
+			{
+				Field: "Token",
+				Reason: `unknown mask "last-4", ` +
+					"expected partial, full, fixed, last4 or sha256",
+			},
+			{
+				Field: "Payments.CVV",
+				Reason: `unknown mask "hidden", ` +
+					"expected partial, full, fixed, last4 or sha256",
+			},
+		},
+		Problems((*maskTypoConfig)(nil), err),
+	)
+}
+
+func TestMaskSuite(t *testing.T) {
+	suite.Run(t, new(MaskTestSuite))
+}
@@ -0,0 +1,399 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Default separators of the slice items and map entries decoded from env values.
+const (
+	DefaultSeparator       = ","
+	DefaultKeyValSeparator = "="
+)
+
+// WithSeparators sets the default separators of the slice items or map entries (e.g. ",") and of
+// the map keys and values (e.g. "="), used by the fields without `envSeparator` and
+// `envKeyValSeparator` tags. Defaults to DefaultSeparator and DefaultKeyValSeparator.
+func WithSeparators(separator string, keyValSeparator string) Option {
+	return func(c *CompositeConfig) {
+		c.separator = separator
+		c.keyValSeparator = keyValSeparator
+	}
+}
+
+// populateEnvFields sets every exported field tagged with `env` from its env variable, or from its
+// `default` tag when the variable is not set or empty, descending into nested structs and non-nil
+// struct pointers. The prefix, extended by the `envPrefix` tags of the nested struct fields, is
+// prepended to the env variable names. It runs before the Populate methods, which can still
+// override the values.
+// A *FieldError is returned for every value that cannot be decoded.
+func (c *CompositeConfig) populateEnvFields(val reflect.Value, path string, prefix string) error {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []error
+	typ := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Field(i)
+		fieldType := typ.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+
+		fieldPath := fieldType.Name
+		if path != "" {
+			fieldPath = path + "." + fieldType.Name
+		}
+
+		key := envTagName(fieldType)
+		if key == "" {
+			fieldPrefix := prefix + fieldType.Tag.Get("envPrefix")
+			if err := c.populateEnvFields(field, fieldPath, fieldPrefix); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		if err := c.populateEnvField(field, fieldType, prefix+key, fieldPath); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// populateEnvField sets a field from its env variable or its default. The field is left unchanged
+// when neither is set. Slices of structs are populated from indexed variables instead, the values
+// of fields with the "json" option are decoded with encoding/json, and the values of []byte fields
+// with the "base64" or "hex" option are decoded from that encoding. Other options are rejected, so
+// a misspelled or unsupported option, e.g. `env:"NAME,required"`, is not silently ignored.
+func (c *CompositeConfig) populateEnvField(
+	field reflect.Value,
+	fieldType reflect.StructField,
+	key string,
+	path string,
+) error {
+	if err := checkEnvOptions(fieldType); err != nil {
+		return &FieldError{Field: path, Err: err}
+	}
+
+	if c.isIndexedField(fieldType) {
+		return c.populateIndexedStructs(field, key, path)
+	}
+
+	value := os.Getenv(key)
+	source := key
+	if value == "" {
+		value = fieldType.Tag.Get("default")
+		source = "default of " + key
+	}
+	if value == "" {
+		return nil
+	}
+
+	var decoded reflect.Value
+	var err error
+	switch {
+	case hasEnvOption(fieldType, "json"):
+		decoded, err = decodeJSON(field.Type(), value)
+	case hasEnvOption(fieldType, "base64"):
+		decoded, err = decodeBinary(field.Type(), value, "base64", decodeBase64)
+	case hasEnvOption(fieldType, "hex"):
+		decoded, err = decodeBinary(field.Type(), value, "hex", hex.DecodeString)
+	default:
+		decoded, err = c.decodeField(field.Type(), value, fieldType.Tag)
+	}
+	if err != nil {
+		return &FieldError{
+			Field: path,
+			Err:   fmt.Errorf("invalid %s value for %s: %w", field.Type(), source, err),
+		}
+	}
+
+	field.Set(decoded)
+	return nil
+}
+
+// decodeField decodes an env value into a new value of a field type. Slices (except []byte) and
+// maps are split with the separators of the field tags, unless they have their own decoder.
+func (c *CompositeConfig) decodeField(
+	typ reflect.Type,
+	value string,
+	tag reflect.StructTag,
+) (reflect.Value, error) {
+	if c.decodesDirectly(typ) {
+		return c.decode(typ, value)
+	}
+
+	separator := c.separator
+	if tagSeparator, ok := tag.Lookup("envSeparator"); ok {
+		separator = tagSeparator
+	}
+	keyValSeparator := c.keyValSeparator
+	if tagSeparator, ok := tag.Lookup("envKeyValSeparator"); ok {
+		keyValSeparator = tagSeparator
+	}
+
+	items := strings.Split(value, separator)
+	switch typ.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(typ, 0, len(items))
+		for i, item := range items {
+			decoded, err := c.decode(typ.Elem(), strings.TrimSpace(item))
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("index %d: %w", i, err)
+			}
+			slice = reflect.Append(slice, decoded)
+		}
+		return slice, nil
+	case reflect.Map:
+		entries := reflect.MakeMapWithSize(typ, len(items))
+		for i, item := range items {
+			mapKey, mapValue, found := strings.Cut(item, keyValSeparator)
+			if !found {
+				return reflect.Value{}, fmt.Errorf(
+					"index %d: missing %q in %q",
+					i,
+					keyValSeparator,
+					item,
+				)
+			}
+
+			decodedKey, err := c.decode(typ.Key(), strings.TrimSpace(mapKey))
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("index %d: key: %w", i, err)
+			}
+			decodedValue, err := c.decode(typ.Elem(), strings.TrimSpace(mapValue))
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("index %d: value: %w", i, err)
+			}
+			entries.SetMapIndex(decodedKey, decodedValue)
+		}
+		return entries, nil
+	default:
+		return c.decode(typ, value)
+	}
+}
+
+// decodeJSON decodes a JSON env value into a new value of a field type. Errors report the byte
+// offset of the JSON value in error.
+func decodeJSON(typ reflect.Type, value string) (reflect.Value, error) {
+	decoded := reflect.New(typ)
+	err := json.Unmarshal([]byte(value), decoded.Interface())
+	if err == nil {
+		return decoded.Elem(), nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return reflect.Value{}, fmt.Errorf("JSON offset %d: %w", syntaxErr.Offset, err)
+	case errors.As(err, &typeErr):
+		return reflect.Value{}, fmt.Errorf("JSON offset %d: %w", typeErr.Offset, err)
+	default:
+		return reflect.Value{}, err
+	}
+}
+
+// decodeBinary decodes an encoded env value into a new value of a []byte field type, e.g. Secret.
+func decodeBinary(
+	typ reflect.Type,
+	value string,
+	encoding string,
+	decode func(string) ([]byte, error),
+) (reflect.Value, error) {
+	if typ.Kind() != reflect.Slice || typ.Elem().Kind() != reflect.Uint8 {
+		return reflect.Value{}, fmt.Errorf("the %s option requires a []byte field", encoding)
+	}
+
+	decoded, err := decode(strings.TrimSpace(value))
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("invalid %s: %w", encoding, err)
+	}
+
+	return reflect.ValueOf(decoded).Convert(typ), nil
+}
+
+// decodeBase64 decodes standard or URL-safe base64, with or without padding.
+func decodeBase64(value string) ([]byte, error) {
+	value = strings.TrimRight(value, "=")
+	if strings.ContainsAny(value, "-_") {
+		return base64.RawURLEncoding.DecodeString(value)
+	}
+
+	return base64.RawStdEncoding.DecodeString(value)
+}
+
+// hasEnvOption reports whether the `env` tag of a field declares an option, e.g. "json" for
+// `env:"FEATURES,json"`.
+func hasEnvOption(field reflect.StructField, option string) bool {
+	_, options, _ := strings.Cut(field.Tag.Get("env"), ",")
+	for _, declared := range strings.Split(options, ",") {
+		if strings.TrimSpace(declared) == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkEnvOptions returns an error when the `env` tag of a field declares an option other than
+// "json", "base64" and "hex".
+func checkEnvOptions(field reflect.StructField) error {
+	_, options, _ := strings.Cut(field.Tag.Get("env"), ",")
+	if options == "" {
+		return nil
+	}
+
+	for _, declared := range strings.Split(options, ",") {
+		switch strings.TrimSpace(declared) {
+		case "json", "base64", "hex":
+		default:
+			return fmt.Errorf(
+				"unknown env tag option %q, expected json, base64 or hex",
+				strings.TrimSpace(declared),
+			)
+		}
+	}
+
+	return nil
+}
+
+// decodesDirectly reports whether values of a type are decoded as a whole rather than split into
+// slice items or map entries.
+func (c *CompositeConfig) decodesDirectly(typ reflect.Type) bool {
+	if _, ok := c.decoders[typ]; ok {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Slice:
+		return typ.Elem().Kind() == reflect.Uint8 ||
+			reflect.PointerTo(typ).Implements(textUnmarshalerType)
+	case reflect.Map:
+		return reflect.PointerTo(typ).Implements(textUnmarshalerType)
+	default:
+		return true
+	}
+}
+
+// isIndexedField reports whether an `env` tagged field is a slice of structs populated from indexed
+// env variables rather than decoded from its own variable.
+func (c *CompositeConfig) isIndexedField(field reflect.StructField) bool {
+	return !hasEnvOption(field, "json") && c.isIndexedStructSlice(field.Type)
+}
+
+// isIndexedStructSlice reports whether a type is a slice of structs (or struct pointers) populated
+// from indexed env variables.
+func (c *CompositeConfig) isIndexedStructSlice(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Slice || c.decodesDirectly(typ) {
+		return false
+	}
+
+	elem := typ.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	_, hasDecoder := c.decoders[elem]
+	return elem.Kind() == reflect.Struct && !hasDecoder && builtinDecoders[elem] == nil &&
+		!reflect.PointerTo(elem).Implements(textUnmarshalerType)
+}
+
+// populateIndexedStructs populates a slice of structs from the env variables prefixed by the key
+// and the element index, e.g. UPSTREAM_0_HOST and UPSTREAM_1_HOST for the HOST field of the
+// elements of an `env:"UPSTREAM"` slice. Indexes start at 0 and must follow each other: a
+// *FieldError names the first missing index, and the elements after it are left out. The slice is
+// left unchanged when no indexed variable is set.
+func (c *CompositeConfig) populateIndexedStructs(
+	field reflect.Value,
+	key string,
+	path string,
+) error {
+	slice := reflect.MakeSlice(field.Type(), 0, 0)
+	var errs []error
+	for i, index := range envIndexes(key) {
+		if index != i {
+			errs = append(errs, &FieldError{
+				Field: path,
+				Err: fmt.Errorf(
+					"missing index %d of %s: no %s* variable is set, but %s* is",
+					i,
+					key,
+					indexedKeyPrefix(key, i),
+					indexedKeyPrefix(key, index),
+				),
+			})
+			break
+		}
+
+		elem := reflect.New(field.Type().Elem()).Elem()
+		target := elem
+		if elem.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(elem.Type().Elem()))
+			target = elem.Elem()
+		}
+
+		elemPath := fmt.Sprintf("%s[%d]", path, i)
+		if err := c.populateEnvFields(target, elemPath, indexedKeyPrefix(key, i)); err != nil {
+			errs = append(errs, err)
+		}
+		slice = reflect.Append(slice, elem)
+	}
+
+	if slice.Len() > 0 {
+		field.Set(slice)
+	}
+
+	return errors.Join(errs...)
+}
+
+// indexedKeyPrefix returns the prefix of the env variables of an element of a slice of structs,
+// e.g. UPSTREAM_0_ for the first element of an `env:"UPSTREAM"` slice.
+func indexedKeyPrefix(key string, index int) string {
+	return key + "_" + strconv.Itoa(index) + "_"
+}
+
+// defaultDecoding populates with the built-in decoders only. It tells which fields are indexed to
+// the functions inspecting a config tree without a CompositeConfig, e.g. Describe and Explain.
+var defaultDecoding = &CompositeConfig{}
+
+// envIndexes returns the sorted indexes of the non-empty indexed env variables of a key, e.g. 0
+// and 2 for UPSTREAM_0_HOST and UPSTREAM_2_PORT with the key UPSTREAM.
+func envIndexes(key string) []int {
+	var indexes []int
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		rest, found := strings.CutPrefix(name, key+"_")
+		if !found || value == "" {
+			continue
+		}
+
+		digits, _, found := strings.Cut(rest, "_")
+		index, err := strconv.Atoi(digits)
+		if found && err == nil && index >= 0 && strconv.Itoa(index) == digits &&
+			!slices.Contains(indexes, index) {
+			indexes = append(indexes, index)
+		}
+	}
+
+	slices.Sort(indexes)
+	return indexes
+}
@@ -0,0 +1,477 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PopulateTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+type populateDatabaseConfig struct {
+	Host    string        `env:"POPULATE_HOST" default:"localhost"`
+	Port    int           `env:"POPULATE_PORT" default:"5432"`
+	Timeout time.Duration `env:"POPULATE_TIMEOUT" default:"5s"`
+	Debug   *bool         `env:"POPULATE_DEBUG"`
+	name    string        `env:"POPULATE_NAME"`
+}
+
+type populateCacheConfig struct {
+	TTL time.Duration `env:"POPULATE_TTL"`
+}
+
+// Populate overrides the values set from the `env` tags.
+func (c *populateCacheConfig) Populate() error {
+	if c.TTL == 0 {
+		c.TTL = time.Minute
+	}
+	return nil
+}
+
+type populateAppConfig struct {
+	Database populateDatabaseConfig
+	Cache    populateCacheConfig
+	Replica  *populateDatabaseConfig
+	Color    decodeColor `env:"POPULATE_COLOR" default:"red"`
+}
+
+func (suite *PopulateTestSuite) SetupTest() {
+	unsetEnv(
+		suite.T(),
+		"POPULATE_HOST", "POPULATE_PORT", "POPULATE_TIMEOUT", "POPULATE_DEBUG", "POPULATE_NAME",
+		"POPULATE_TTL", "POPULATE_COLOR", "UPSTREAM_0_HOST", "UPSTREAM_X_HOST", "UPSTREAM_HOST",
+		"UPSTREAM_0_HSOT", "UPSTREAM_1_HOST", "UPSTREAM_3_HOST",
+	)
+
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *PopulateTestSuite) TestItPopulatesTaggedFieldsFromEnvAndDefaults() {
+	suite.T().Setenv("POPULATE_PORT", "6432")
+	suite.T().Setenv("POPULATE_DEBUG", "true")
+	suite.T().Setenv("POPULATE_NAME", "ignored")
+	appConfig := &populateAppConfig{Replica: &populateDatabaseConfig{}}
+
+	err := NewCompositeConfig(nil, WithDecoder(parseDecodeColor)).
+		PopulateAndValidate(appConfig, "test", suite.dir)
+
+	suite.Require().NoError(err)
+	debug := true
+	database := populateDatabaseConfig{
+		Host:    "localhost",
+		Port:    6432,
+		Timeout: 5 * time.Second,
+		Debug:   &debug,
+	}
+	replica := database
+	suite.Assert().Equal(
+		&populateAppConfig{
+			Database: database,
+			Cache:    populateCacheConfig{TTL: time.Minute},
+			Replica:  &replica,
+			Color:    decodeRed,
+		},
+		appConfig,
+	)
+	suite.Assert().Equal(SourceDefault, OriginOf("POPULATE_HOST").Source)
+}
+
+func (suite *PopulateTestSuite) TestItPopulatesTaggedFieldsBeforePopulateMethods() {
+	suite.T().Setenv("POPULATE_TTL", "10s")
+	suite.T().Setenv("POPULATE_COLOR", "blue")
+
+	appConfig, err := Load[populateAppConfig](
+		WithDir(suite.dir),
+		WithDecoder(parseDecodeColor),
+	)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(10*time.Second, appConfig.Cache.TTL)
+	suite.Assert().Equal(decodeBlue, appConfig.Color)
+	suite.Assert().Nil(appConfig.Replica, "nil struct pointers are not allocated")
+}
+
+func (suite *PopulateTestSuite) TestItReportsEveryInvalidValue() {
+	suite.T().Setenv("POPULATE_PORT", "http")
+	suite.T().Setenv("POPULATE_COLOR", "green")
+
+	_, err := Load[populateAppConfig](
+		WithDir(suite.dir),
+		WithDecoder(parseDecodeColor),
+	)
+
+	suite.Require().Error(err)
+	var fieldErr *FieldError
+	suite.Require().True(errors.As(err, &fieldErr))
+	suite.Assert().Equal(
+		[]Problem{
+			{
+				Field: "Database.Port",
+				Env:   "POPULATE_PORT",
+				Reason: "invalid int value for POPULATE_PORT: " +
+					`strconv.ParseInt: parsing "http": invalid syntax`,
+				Origin: Origin{Key: "POPULATE_PORT", Source: SourceProcessEnv},
+			},
+			{
+				Field:  "Color",
+				Env:    "POPULATE_COLOR",
+				Reason: "invalid config.decodeColor value for POPULATE_COLOR: unknown color",
+				Origin: Origin{Key: "POPULATE_COLOR", Source: SourceProcessEnv},
+			},
+		},
+		Problems((*populateAppConfig)(nil), err),
+	)
+}
+
+func (suite *PopulateTestSuite) TestItReportsInvalidDefaults() {
+	type appConfig struct {
+		Timeout time.Duration `env:"POPULATE_TIMEOUT" default:"soon"`
+	}
+
+	_, err := Load[appConfig](WithDir(suite.dir))
+
+	suite.Require().Error(err)
+	suite.Assert().Contains(
+		err.Error(),
+		"failed to populate env fields: failed to populate field Timeout: "+
+			"invalid time.Duration value for default of POPULATE_TIMEOUT",
+	)
+}
+
+type populateUpstream struct {
+	Host   string `env:"HOST"`
+	Port   int    `env:"PORT" default:"80"`
+	Weight *int   `env:"WEIGHT"`
+}
+
+type populateCollectionsConfig struct {
+	Ports     []int               `env:"POPULATE_PORTS"`
+	Labels    map[string]string   `env:"POPULATE_LABELS"`
+	Hosts     []string            `env:"POPULATE_HOSTS" envSeparator:";"`
+	Limits    map[string]int      `env:"POPULATE_LIMITS" envSeparator:"|" envKeyValSeparator:":"`
+	Upstreams []populateUpstream  `env:"UPSTREAM"`
+	Backups   []*populateUpstream `env:"BACKUP"`
+}
+
+func (suite *PopulateTestSuite) setEnv(values map[string]string) {
+	for key, value := range values {
+		suite.T().Setenv(key, value)
+	}
+}
+
+func (suite *PopulateTestSuite) TestItPopulatesSlicesAndMaps() {
+	suite.setEnv(map[string]string{
+		"POPULATE_PORTS":  "80, 443",
+		"POPULATE_LABELS": "team=core,tier = 1",
+		"POPULATE_HOSTS":  "a.local;b.local",
+		"POPULATE_LIMITS": "read:10|write:5",
+	})
+
+	appConfig, err := Load[populateCollectionsConfig](WithDir(suite.dir))
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]int{80, 443}, appConfig.Ports)
+	suite.Assert().Equal(map[string]string{"team": "core", "tier": "1"}, appConfig.Labels)
+	suite.Assert().Equal([]string{"a.local", "b.local"}, appConfig.Hosts)
+	suite.Assert().Equal(map[string]int{"read": 10, "write": 5}, appConfig.Limits)
+}
+
+func (suite *PopulateTestSuite) TestItCanChangeTheDefaultSeparators() {
+	suite.setEnv(map[string]string{"POPULATE_PORTS": "80 443", "POPULATE_LABELS": "team:core"})
+
+	appConfig, err := Load[populateCollectionsConfig](WithDir(suite.dir), WithSeparators(" ", ":"))
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]int{80, 443}, appConfig.Ports)
+	suite.Assert().Equal(map[string]string{"team": "core"}, appConfig.Labels)
+}
+
+func (suite *PopulateTestSuite) TestItPopulatesSlicesOfStructsFromIndexedVars() {
+	suite.setEnv(map[string]string{
+		"UPSTREAM_0_HOST":   "a.local",
+		"UPSTREAM_0_PORT":   "8080",
+		"UPSTREAM_1_HOST":   "b.local",
+		"UPSTREAM_1_WEIGHT": "3",
+		"BACKUP_0_HOST":     "backup.local",
+	})
+
+	appConfig, err := Load[populateCollectionsConfig](WithDir(suite.dir))
+
+	suite.Require().NoError(err)
+	weight := 3
+	suite.Assert().Equal(
+		[]populateUpstream{
+			{Host: "a.local", Port: 8080},
+			{Host: "b.local", Port: 80, Weight: &weight},
+		},
+		appConfig.Upstreams,
+	)
+	suite.Assert().Equal([]*populateUpstream{{Host: "backup.local", Port: 80}}, appConfig.Backups)
+}
+
+func (suite *PopulateTestSuite) TestItReportsTheOffendingIndex() {
+	testCases := []struct {
+		name    string
+		env     map[string]string
+		message string
+	}{
+		{
+			name:    "slice item",
+			env:     map[string]string{"POPULATE_PORTS": "80,http"},
+			message: "invalid []int value for POPULATE_PORTS: index 1: strconv.ParseInt",
+		},
+		{
+			name: "map entry",
+			env:  map[string]string{"POPULATE_LABELS": "team=core,tier"},
+			message: "invalid map[string]string value for POPULATE_LABELS: " +
+				`index 1: missing "=" in "tier"`,
+		},
+		{
+			name:    "map value",
+			env:     map[string]string{"POPULATE_LIMITS": "read:10|write:many"},
+			message: "index 1: value: strconv.ParseInt",
+		},
+		{
+			name: "indexed struct",
+			env:  map[string]string{"UPSTREAM_0_HOST": "a.local", "UPSTREAM_1_PORT": "http"},
+			message: "failed to populate field Upstreams[1].Port: " +
+				"invalid int value for UPSTREAM_1_PORT",
+		},
+		{
+			name: "missing first index",
+			env:  map[string]string{"UPSTREAM_1_HOST": "b.local"},
+			message: "failed to populate field Upstreams: missing index 0 of UPSTREAM: " +
+				"no UPSTREAM_0_* variable is set, but UPSTREAM_1_* is",
+		},
+		{
+			name: "index gap",
+			env: map[string]string{
+				"UPSTREAM_0_HOST": "a.local",
+				"UPSTREAM_1_HOST": "b.local",
+				"UPSTREAM_3_HOST": "d.local",
+			},
+			message: "missing index 2 of UPSTREAM: no UPSTREAM_2_* variable is set, " +
+				"but UPSTREAM_3_* is",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.SetupTest()
+			suite.setEnv(testCase.env)
+
+			_, err := Load[populateCollectionsConfig](WithDir(suite.dir))
+
+			suite.Require().Error(err)
+			suite.Assert().Contains(err.Error(), testCase.message)
+		})
+	}
+}
+
+func (suite *PopulateTestSuite) TestItOnlyReportsUnknownIndexedKeysAsUnused() {
+	envFile := "UPSTREAM_0_HOST=a.local\nUPSTREAM_X_HOST=b.local\nUPSTREAM_HOST=c.local\n" +
+		"UPSTREAM_0_HSOT=typo.local\n"
+	suite.Require().NoError(os.WriteFile(filepath.Join(suite.dir, ".env"), []byte(envFile), 0644))
+
+	_, err := Load[populateCollectionsConfig](WithDir(suite.dir), WithUnusedKeys(FailOnUnusedKeys))
+
+	var unusedErr *UnusedKeysError
+	suite.Require().True(errors.As(err, &unusedErr))
+	var keys []string
+	for _, key := range unusedErr.Keys {
+		keys = append(keys, key.Key)
+	}
+	suite.Assert().Equal(
+		[]string{"UPSTREAM_X_HOST", "UPSTREAM_HOST", "UPSTREAM_0_HSOT"},
+		keys,
+	)
+}
+
+type populateFeatures struct {
+	Search bool `json:"search"`
+	Limit  int  `json:"limit"`
+}
+
+type populateJSONConfig struct {
+	Features  populateFeatures   `env:"POPULATE_FEATURES,json"`
+	Flags     map[string]bool    `env:"POPULATE_FLAGS,json" default:"{\"beta\": true}"`
+	Upstreams []populateUpstream `env:"POPULATE_UPSTREAMS, json"`
+}
+
+func (suite *PopulateTestSuite) TestItDecodesJSONValues() {
+	suite.setEnv(map[string]string{
+		"POPULATE_FEATURES":  `{"search": true, "limit": 10}`,
+		"POPULATE_UPSTREAMS": `[{"Host": "a.local", "Port": 8080}]`,
+	})
+
+	appConfig, err := Load[populateJSONConfig](WithDir(suite.dir))
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(populateFeatures{Search: true, Limit: 10}, appConfig.Features)
+	suite.Assert().Equal(map[string]bool{"beta": true}, appConfig.Flags)
+	suite.Assert().Equal([]populateUpstream{{Host: "a.local", Port: 8080}}, appConfig.Upstreams)
+}
+
+func (suite *PopulateTestSuite) TestItReportsTheJSONOffsetOfInvalidValues() {
+	testCases := []struct {
+		name    string
+		value   string
+		message string
+	}{
+		{
+			name:    "syntax error",
+			value:   `{"search": tru}`,
+			message: "for POPULATE_FEATURES: JSON offset 15: invalid character",
+		},
+		{
+			name:  "type error",
+			value: `{"search": true, "limit": "ten"}`,
+			message: "for POPULATE_FEATURES: JSON offset 31: json: cannot unmarshal string " +
+				"into Go struct field populateFeatures.limit of type int",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.T().Setenv("POPULATE_FEATURES", testCase.value)
+
+			_, err := Load[populateJSONConfig](WithDir(suite.dir))
+
+			suite.Require().Error(err)
+			suite.Assert().Contains(err.Error(), testCase.message)
+		})
+	}
+}
+
+type populateKeysConfig struct {
+	SigningKey Secret `env:"POPULATE_SIGNING_KEY,base64" validate:"len=16"`
+	Salt       []byte `env:"POPULATE_SALT,hex" validate:"min=4"`
+	Raw        []byte `env:"POPULATE_RAW"`
+}
+
+func (suite *PopulateTestSuite) TestItDecodesBase64AndHexValues() {
+	testCases := []struct {
+		name       string
+		signingKey string
+	}{
+		{"standard base64", "AAECAwQFBgcICQoLDA0ODw=="},
+		{"unpadded base64", "AAECAwQFBgcICQoLDA0ODw"},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.setEnv(map[string]string{
+				"POPULATE_SIGNING_KEY": testCase.signingKey,
+				"POPULATE_SALT":        "deadbeef",
+				"POPULATE_RAW":         "raw",
+			})
+
+			appConfig, err := Load[populateKeysConfig](WithDir(suite.dir))
+
+			suite.Require().NoError(err)
+			suite.Assert().Equal(
+				Secret{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+				appConfig.SigningKey,
+			)
+			suite.Assert().Equal([]byte{0xde, 0xad, 0xbe, 0xef}, appConfig.Salt)
+			suite.Assert().Equal([]byte("raw"), appConfig.Raw)
+		})
+	}
+
+}
+
+func (suite *PopulateTestSuite) TestItDecodesURLSafeBase64Values() {
+	suite.setEnv(map[string]string{
+		"POPULATE_SIGNING_KEY": "-_-_-_-_-_-_-_-_-_-_-w",
+		"POPULATE_SALT":        "deadbeef",
+	})
+
+	appConfig, err := Load[populateKeysConfig](WithDir(suite.dir))
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(byte(0xfb), appConfig.SigningKey[0])
+}
+
+func (suite *PopulateTestSuite) TestItValidatesTheDecodedBytes() {
+	suite.setEnv(map[string]string{
+		"POPULATE_SIGNING_KEY": "c2hvcnQ=",
+		"POPULATE_SALT":        "deadbeef",
+	})
+
+	_, err := Load[populateKeysConfig](WithDir(suite.dir))
+
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "'SigningKey' failed on the 'len' tag")
+}
+
+func (suite *PopulateTestSuite) TestItReportsInvalidEncodedValues() {
+	testCases := []struct {
+		name    string
+		env     map[string]string
+		message string
+	}{
+		{
+			name:    "base64",
+			env:     map[string]string{"POPULATE_SIGNING_KEY": "not base64!"},
+			message: "invalid config.Secret value for POPULATE_SIGNING_KEY: invalid base64",
+		},
+		{
+			name:    "hex",
+			env:     map[string]string{"POPULATE_SALT": "xyz"},
+			message: "invalid []uint8 value for POPULATE_SALT: invalid hex",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.SetupTest()
+			suite.setEnv(testCase.env)
+
+			_, err := Load[populateKeysConfig](WithDir(suite.dir))
+
+			suite.Require().Error(err)
+			suite.Assert().Contains(err.Error(), testCase.message)
+		})
+	}
+}
+
+func (suite *PopulateTestSuite) TestItRequiresByteFieldsForEncodedValues() {
+	type appConfig struct {
+		Key string `env:"POPULATE_SIGNING_KEY,hex"`
+	}
+	suite.T().Setenv("POPULATE_SIGNING_KEY", "00")
+
+	_, err := Load[appConfig](WithDir(suite.dir))
+
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "the hex option requires a []byte field")
+}
+
+func (suite *PopulateTestSuite) TestItRejectsUnknownEnvTagOptions() {
+	type appConfig struct {
+		Name  string   `env:"POPULATE_NAME,required"`
+		Hosts []string `env:"POPULATE_HOSTS, json"`
+	}
+
+	_, err := Load[appConfig](WithDir(suite.dir))
+
+	var fieldErr *FieldError
+	suite.Require().ErrorAs(err, &fieldErr)
+	suite.Assert().Equal("Name", fieldErr.Field)
+	suite.Assert().Contains(
+		err.Error(),
+		`unknown env tag option "required", expected json, base64 or hex`,
+	)
+	suite.Assert().NotContains(err.Error(), "Hosts")
+}
+
+func TestPopulateSuite(t *testing.T) {
+	suite.Run(t, new(PopulateTestSuite))
+}
@@ -0,0 +1,16 @@
+// Package presets provides ready-made config blocks for the settings most services share: an
+// HTTP server, a SQL database, Redis and logging. They implement config.Config and are populated
+// from `env` tags with sensible defaults, so they plug directly into a composite config:
+//
+//	type AppConfig struct {
+//		HTTP     presets.HTTPServer
+//		Database presets.Database
+//		Cache    presets.Redis
+//		Log      presets.Logging
+//	}
+//
+//	appConfig := config.MustLoad[AppConfig]()
+//
+// The `envPrefix` tag of the field holding a preset prefixes its env vars, e.g. a Database field
+// tagged `envPrefix:"ORDERS_"` is read from ORDERS_DB_HOST, ORDERS_DB_PORT and so on.
+package presets
@@ -0,0 +1,44 @@
+package presets
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPServer is the config of an HTTP server, read from the HTTP_* env vars.
+type HTTPServer struct {
+	Host string `env:"HTTP_HOST" default:"0.0.0.0" desc:"Listen host"`
+	Port int    `env:"HTTP_PORT" default:"8080" validate:"min=1,max=65535" desc:"Listen port"`
+	// ReadTimeout is the maximum duration for reading a whole request, including the body.
+	ReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" default:"15s"`
+	ReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" default:"5s"`
+	WriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" default:"15s"`
+	IdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" default:"60s"`
+	// ShutdownTimeout is the time given to the in-flight requests on shutdown, e.g. to the context
+	// passed to http.Server.Shutdown.
+	ShutdownTimeout time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT" default:"10s"`
+}
+
+// Populate implements config.Config. The fields are populated from their `env` tags.
+func (s *HTTPServer) Populate() error {
+	return nil
+}
+
+// Addr returns the listen address, e.g. "0.0.0.0:8080".
+func (s *HTTPServer) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// Server returns an http.Server serving a handler with the listen address and timeouts.
+func (s *HTTPServer) Server(handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              s.Addr(),
+		Handler:           handler,
+		ReadTimeout:       s.ReadTimeout,
+		ReadHeaderTimeout: s.ReadHeaderTimeout,
+		WriteTimeout:      s.WriteTimeout,
+		IdleTimeout:       s.IdleTimeout,
+	}
+}
@@ -0,0 +1,91 @@
+package presets
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golibry/go-config/config"
+	"github.com/stretchr/testify/suite"
+)
+
+type HTTPServerTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+type httpAppConfig struct {
+	Public HTTPServer
+	Admin  HTTPServer `envPrefix:"ADMIN_"`
+}
+
+func (suite *HTTPServerTestSuite) SetupTest() {
+	unsetEnv(suite.T(), "HTTP_PORT", "HTTP_WRITE_TIMEOUT", "ADMIN_HTTP_HOST", "ADMIN_HTTP_PORT")
+	suite.dir = suite.T().TempDir()
+}
+
+// unsetEnv unsets env vars for the duration of a test.
+func unsetEnv(t *testing.T, keys ...string) {
+	for _, key := range keys {
+		t.Setenv(key, "")
+		_ = os.Unsetenv(key)
+	}
+}
+
+func (suite *HTTPServerTestSuite) TestItLoadsDefaultsAndPrefixedSettings() {
+	suite.T().Setenv("HTTP_WRITE_TIMEOUT", "1m")
+	suite.T().Setenv("ADMIN_HTTP_HOST", "127.0.0.1")
+	suite.T().Setenv("ADMIN_HTTP_PORT", "9090")
+
+	appConfig, err := config.Load[httpAppConfig](config.WithDir(suite.dir))
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(
+		HTTPServer{
+			Host:              "0.0.0.0",
+			Port:              8080,
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      time.Minute,
+			IdleTimeout:       time.Minute,
+			ShutdownTimeout:   10 * time.Second,
+		},
+		appConfig.Public,
+	)
+	suite.Assert().Equal("127.0.0.1:9090", appConfig.Admin.Addr())
+}
+
+func (suite *HTTPServerTestSuite) TestItBuildsAServer() {
+	serverConfig := HTTPServer{
+		Host:              "::1",
+		Port:              8443,
+		ReadTimeout:       time.Second,
+		ReadHeaderTimeout: 2 * time.Second,
+		WriteTimeout:      3 * time.Second,
+		IdleTimeout:       4 * time.Second,
+	}
+	handler := http.NewServeMux()
+
+	server := serverConfig.Server(handler)
+
+	suite.Assert().Equal("[::1]:8443", server.Addr)
+	suite.Assert().Same(handler, server.Handler)
+	suite.Assert().Equal(time.Second, server.ReadTimeout)
+	suite.Assert().Equal(2*time.Second, server.ReadHeaderTimeout)
+	suite.Assert().Equal(3*time.Second, server.WriteTimeout)
+	suite.Assert().Equal(4*time.Second, server.IdleTimeout)
+}
+
+func (suite *HTTPServerTestSuite) TestItValidatesThePort() {
+	suite.T().Setenv("HTTP_PORT", "70000")
+
+	_, err := config.Load[httpAppConfig](config.WithDir(suite.dir))
+
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "Public.Port")
+}
+
+func TestHTTPServerSuite(t *testing.T) {
+	suite.Run(t, new(HTTPServerTestSuite))
+}
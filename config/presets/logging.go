@@ -0,0 +1,53 @@
+package presets
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// The log formats supported by Logging.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Logging is the config of a log/slog logger, read from the LOG_* env vars.
+type Logging struct {
+	// Level is one of debug, info, warn and error, optionally with an offset, e.g. "debug-2".
+	Level     slog.Level `env:"LOG_LEVEL" default:"info" desc:"Minimum log level"`
+	Format    string     `env:"LOG_FORMAT" default:"text" validate:"oneof=text json"`
+	AddSource bool       `env:"LOG_ADD_SOURCE" desc:"Log the source file and line"`
+}
+
+// Populate checks the log format.
+func (l *Logging) Populate() error {
+	switch l.Format {
+	case "":
+		l.Format = LogFormatText
+	case LogFormatText, LogFormatJSON:
+	default:
+		return fmt.Errorf("unsupported log format %q, expected text or json", l.Format)
+	}
+
+	return nil
+}
+
+// HandlerOptions returns the slog handler options of the level and source settings.
+func (l *Logging) HandlerOptions() *slog.HandlerOptions {
+	return &slog.HandlerOptions{Level: l.Level, AddSource: l.AddSource}
+}
+
+// Handler returns a text or JSON slog handler writing to w, depending on the format.
+func (l *Logging) Handler(w io.Writer) slog.Handler {
+	if l.Format == LogFormatJSON {
+		return slog.NewJSONHandler(w, l.HandlerOptions())
+	}
+
+	return slog.NewTextHandler(w, l.HandlerOptions())
+}
+
+// Logger returns a logger writing to w with the handler of the settings.
+func (l *Logging) Logger(w io.Writer) *slog.Logger {
+	return slog.New(l.Handler(w))
+}
@@ -0,0 +1,74 @@
+package presets
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/golibry/go-config/config"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoggingTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+type loggingAppConfig struct {
+	Log Logging
+}
+
+func (suite *LoggingTestSuite) SetupTest() {
+	unsetEnv(suite.T(), "LOG_LEVEL", "LOG_FORMAT", "LOG_ADD_SOURCE")
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *LoggingTestSuite) TestItLoadsTheDefaults() {
+	appConfig, err := config.Load[loggingAppConfig](config.WithDir(suite.dir))
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(Logging{Level: slog.LevelInfo, Format: LogFormatText}, appConfig.Log)
+}
+
+func (suite *LoggingTestSuite) TestItBuildsAJSONLogger() {
+	suite.T().Setenv("LOG_LEVEL", "warn")
+	suite.T().Setenv("LOG_FORMAT", "json")
+	appConfig, err := config.Load[loggingAppConfig](config.WithDir(suite.dir))
+	suite.Require().NoError(err)
+	var output bytes.Buffer
+
+	logger := appConfig.Log.Logger(&output)
+	logger.Info("hidden")
+	logger.Warn("shown")
+
+	suite.Assert().Equal(slog.LevelWarn, appConfig.Log.Level)
+	suite.Assert().NotContains(output.String(), "hidden")
+	suite.Assert().Contains(output.String(), `"msg":"shown"`)
+}
+
+func (suite *LoggingTestSuite) TestItRejectsInvalidSettings() {
+	testCases := []struct {
+		name    string
+		key     string
+		value   string
+		message string
+	}{
+		{"unknown level", "LOG_LEVEL", "verbose", "invalid slog.Level value for LOG_LEVEL"},
+		{"unknown format", "LOG_FORMAT", "xml", `unsupported log format "xml"`},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.T().Setenv(testCase.key, testCase.value)
+
+			_, err := config.Load[loggingAppConfig](config.WithDir(suite.dir))
+
+			suite.Require().Error(err)
+			suite.Assert().Contains(err.Error(), testCase.message)
+		})
+	}
+}
+
+func TestLoggingSuite(t *testing.T) {
+	suite.Run(t, new(LoggingTestSuite))
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ExitConfigError is the exit code used by MustLoad when the configuration is invalid (EX_CONFIG
+// from sysexits.h).
+const ExitConfigError = 78
+
+// osExit and stderr are replaced in tests.
+var (
+	osExit           = os.Exit
+	stderr io.Writer = os.Stderr
+)
+
+// FieldError is returned when populating a config field fails.
+type FieldError struct {
+	// Field is the dotted Go field path, e.g. "Database.Primary".
+	Field string
+	Err   error
+}
+
+// Error describes the failure along with the field path.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("failed to populate field %s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the error returned by the field's Populate method.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Problem describes a single problem found while loading a config.
+type Problem struct {
+	// Field is the dotted Go field path, if the problem concerns a field.
+	Field string
+	// Env is the env variable of the field, or the unused key, if any.
+	Env    string
+	Reason string
+	// Origin is the origin of the Env value. It is only set when Env is set.
+	Origin Origin
+}
+
+// ReportFunc writes the report of the problems making MustLoad fail.
+type ReportFunc func(w io.Writer, problems []Problem)
+
+// WithReport sets the function writing the report of the problems making MustLoad fail to stderr.
+// Defaults to WriteProblemReport.
+func WithReport(report ReportFunc) Option {
+	return func(c *CompositeConfig) {
+		c.reportProblems = report
+	}
+}
+
+// MustLoad loads the config like Load. On failure, it writes a report of every problem found to
+// stderr and exits with ExitConfigError, so main functions don't have to handle the error:
+//
+//	appConfig := config.MustLoad[AppConfig](config.WithEnvDetection("APP_ENV"))
+func MustLoad[T any](opts ...Option) *T {
+	c := NewCompositeConfig(nil, opts...)
+
+	config, err := load[T](c)
+	if err != nil {
+		report := c.reportProblems
+		if report == nil {
+			report = WriteProblemReport
+		}
+		report(stderr, Problems((*T)(nil), err))
+		osExit(ExitConfigError)
+	}
+
+	return config
+}
+
+// Problems splits an error returned while loading a config into problems: one per failed field
+// population, unused env file key and failed validation rule, or a single problem for other errors.
+// The validation rules failed by fields whose population failed are not reported again. The config
+// (which can be a nil pointer) is used to find the env variables of the fields.
+func Problems(config interface{}, err error) []Problem {
+	if err == nil {
+		return nil
+	}
+
+	envByField := make(map[string]string)
+	for _, spec := range Describe(config) {
+		envByField[spec.Path] = spec.Env
+	}
+	fieldProblem := func(field string, reason string) Problem {
+		problem := Problem{Field: field, Env: envByField[field], Reason: reason}
+		if problem.Env != "" {
+			problem.Origin = OriginOf(problem.Env)
+		}
+		return problem
+	}
+
+	var problems []Problem
+	failedFields := make(map[string]bool)
+	for _, fieldErr := range fieldErrors(err) {
+		problems = append(problems, fieldProblem(fieldErr.Field, fieldErr.Err.Error()))
+		failedFields[fieldErr.Field] = true
+	}
+
+	var unusedKeysErr *UnusedKeysError
+	if errors.As(err, &unusedKeysErr) {
+		for _, key := range unusedKeysErr.Keys {
+			problems = append(problems, Problem{Env: key.Key, Reason: "unused key", Origin: key})
+		}
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		for _, fieldErr := range validationErrors {
+			if path := fieldPath(fieldErr); !failedFields[path] {
+				problems = append(problems, fieldProblem(path, validationReason(fieldErr)))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		problems = append(problems, Problem{Reason: err.Error()})
+	}
+
+	return problems
+}
+
+// WriteProblemReport writes the problems as an aligned table with the env variable, field path,
+// reason and source of each problem. It is the default report of MustLoad.
+func WriteProblemReport(w io.Writer, problems []Problem) {
+	_, _ = fmt.Fprintf(w, "Configuration error: %d problem(s)\n", len(problems))
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(table, "  ENV\tFIELD\tREASON\tSOURCE")
+	for _, problem := range problems {
+		source := ""
+		if problem.Env != "" {
+			source = problem.Origin.String()
+		}
+
+		cells := []string{problem.Env, problem.Field, problem.Reason, source}
+		for i, cell := range cells {
+			if cell == "" {
+				cells[i] = "-"
+			}
+			cells[i] = strings.ReplaceAll(cells[i], "\n", " ")
+		}
+		_, _ = fmt.Fprintln(table, "  "+strings.Join(cells, "\t"))
+	}
+	_ = table.Flush()
+}
+
+// fieldErrors returns the field errors of an error tree.
+func fieldErrors(err error) []*FieldError {
+	if fieldErr, ok := err.(*FieldError); ok {
+		return []*FieldError{fieldErr}
+	}
+
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() []error }:
+		var fieldErrs []*FieldError
+		for _, err := range wrapped.Unwrap() {
+			fieldErrs = append(fieldErrs, fieldErrors(err)...)
+		}
+		return fieldErrs
+	case interface{ Unwrap() error }:
+		return fieldErrors(wrapped.Unwrap())
+	default:
+		return nil
+	}
+}
+
+// fieldPath returns the dotted field path of a validation error, without the root struct name.
+func fieldPath(fieldErr validator.FieldError) string {
+	_, path, found := strings.Cut(fieldErr.StructNamespace(), ".")
+	if !found {
+		return fieldErr.StructField()
+	}
+
+	return path
+}
+
+// validationReason describes the validation rule a field failed.
+func validationReason(fieldErr validator.FieldError) string {
+	rule := fieldErr.Tag()
+	if fieldErr.Param() != "" {
+		rule += "=" + fieldErr.Param()
+	}
+
+	return fmt.Sprintf("failed %q validation", rule)
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ProblemsTestSuite struct {
+	suite.Suite
+	dir      string
+	exitCode int
+	output   bytes.Buffer
+}
+
+type problemsDatabaseConfig struct {
+	Host string `env:"PROBLEMS_HOST" validate:"required"`
+	Port int    `env:"PROBLEMS_PORT" validate:"min=1"`
+}
+
+func (d *problemsDatabaseConfig) Populate() error {
+	d.Host = os.Getenv("PROBLEMS_HOST")
+	d.Port, _ = strconv.Atoi(os.Getenv("PROBLEMS_PORT"))
+	return nil
+}
+
+type problemsServerConfig struct {
+	Port    int    `env:"PROBLEMS_PORT" validate:"min=1"`
+	Host    string `env:"PROBLEMS_HOST" validate:"required"`
+	Timeout int    `env:"PROBLEMS_TIMEOUT" validate:"min=1"`
+}
+
+type problemsFailingConfig struct {
+	Name string
+}
+
+func (f *problemsFailingConfig) Populate() error {
+	return errors.New("populate failed")
+}
+
+type problemsAppConfig struct {
+	Database problemsDatabaseConfig
+}
+
+type problemsFailingAppConfig struct {
+	Cache  problemsFailingConfig
+	Nested struct {
+		Queue problemsFailingConfig
+	}
+}
+
+func (suite *ProblemsTestSuite) SetupTest() {
+	unsetEnv(suite.T(), "PROBLEMS_HOST", "PROBLEMS_PORT", "PROBLEMS_TIMEOUT")
+	suite.dir = suite.T().TempDir()
+
+	suite.exitCode = -1
+	suite.output.Reset()
+	osExit = func(code int) {
+		suite.exitCode = code
+	}
+	stderr = &suite.output
+}
+
+func (suite *ProblemsTestSuite) TearDownTest() {
+	osExit = os.Exit
+	stderr = os.Stderr
+}
+
+func (suite *ProblemsTestSuite) TestItListsValidationProblems() {
+	_, err := Load[problemsAppConfig](WithDir(suite.dir))
+
+	suite.Assert().Equal(
+		[]Problem{
+			{
+				Field:  "Database.Host",
+				Env:    "PROBLEMS_HOST",
+				Reason: `failed "required" validation`,
+				Origin: Origin{Key: "PROBLEMS_HOST", Source: SourceDefault},
+			},
+			{
+				Field:  "Database.Port",
+				Env:    "PROBLEMS_PORT",
+				Reason: `failed "min=1" validation`,
+				Origin: Origin{Key: "PROBLEMS_PORT", Source: SourceDefault},
+			},
+		},
+		Problems((*problemsAppConfig)(nil), err),
+	)
+}
+
+func (suite *ProblemsTestSuite) TestItListsEveryPopulationProblem() {
+	_, err := Load[problemsFailingAppConfig](WithDir(suite.dir))
+
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "failed to populate nested configs")
+	suite.Assert().Equal(
+		[]Problem{
+			{Field: "Cache", Reason: "populate failed"},
+			{Field: "Nested.Queue", Reason: "populate failed"},
+		},
+		Problems(&problemsFailingAppConfig{}, err),
+	)
+}
+
+func (suite *ProblemsTestSuite) TestItListsUnusedKeysAndOtherErrors() {
+	unused := Origin{Key: "PROBLEMS_HSOT", Source: SourceFile, File: ".env", Line: 2}
+	err := errors.Join(errors.New("env files check failed"), &UnusedKeysError{Keys: []Origin{unused}})
+
+	suite.Assert().Equal(
+		[]Problem{{Env: "PROBLEMS_HSOT", Reason: "unused key", Origin: unused}},
+		Problems(nil, err),
+	)
+	suite.Assert().Equal([]Problem{{Reason: "boom"}}, Problems(nil, errors.New("boom")))
+	suite.Assert().Nil(Problems(nil, nil))
+}
+
+func (suite *ProblemsTestSuite) TestItWritesAnAlignedReport() {
+	var output bytes.Buffer
+	problems := []Problem{
+		{
+			Field:  "Database.Host",
+			Env:    "DB_HOST",
+			Reason: `failed "required" validation`,
+			Origin: Origin{Key: "DB_HOST", Source: SourceFile, File: ".env", Line: 3},
+		},
+		{Field: "Cache", Reason: "dial failed:\nconnection refused"},
+	}
+
+	WriteProblemReport(&output, problems)
+
+	suite.Assert().Equal(
+		"Configuration error: 2 problem(s)\n"+
+			"  ENV      FIELD          REASON                           SOURCE\n"+
+			"  DB_HOST  Database.Host  failed \"required\" validation     .env:3\n"+
+			"  -        Cache          dial failed: connection refused  -\n",
+		output.String(),
+	)
+}
+
+func (suite *ProblemsTestSuite) TestItExitsWithAReportWhenLoadingFails() {
+	appConfig := MustLoad[problemsAppConfig](WithDir(suite.dir))
+
+	suite.Assert().Nil(appConfig)
+	suite.Assert().Equal(ExitConfigError, suite.exitCode)
+	suite.Assert().Contains(suite.output.String(), "Configuration error: 2 problem(s)\n")
+	suite.Assert().Contains(suite.output.String(), "PROBLEMS_HOST")
+}
+
+func (suite *ProblemsTestSuite) TestItReportsPopulationAndValidationProblemsTogether() {
+	suite.T().Setenv("PROBLEMS_PORT", "http")
+	suite.T().Setenv("PROBLEMS_TIMEOUT", "5")
+	var reported []Problem
+	report := func(_ io.Writer, problems []Problem) {
+		reported = problems
+	}
+
+	appConfig := MustLoad[problemsServerConfig](WithDir(suite.dir), WithReport(report))
+
+	suite.Assert().Nil(appConfig)
+	suite.Assert().Equal(ExitConfigError, suite.exitCode)
+	suite.Require().Len(reported, 2)
+	suite.Assert().Equal("Port", reported[0].Field)
+	suite.Assert().Contains(reported[0].Reason, `invalid int value for PROBLEMS_PORT`)
+	suite.Assert().Equal(
+		Problem{
+			Field:  "Host",
+			Env:    "PROBLEMS_HOST",
+			Reason: `failed "required" validation`,
+			Origin: Origin{Key: "PROBLEMS_HOST", Source: SourceDefault},
+		},
+		reported[1],
+	)
+}
+
+func (suite *ProblemsTestSuite) TestItCanCustomizeTheReport() {
+	var reported []Problem
+	report := func(w io.Writer, problems []Problem) {
+		reported = problems
+		_, _ = io.WriteString(w, "invalid config\n")
+	}
+
+	MustLoad[problemsFailingAppConfig](WithDir(suite.dir), WithReport(report))
+
+	suite.Assert().Equal(ExitConfigError, suite.exitCode)
+	suite.Assert().Equal("invalid config\n", suite.output.String())
+	suite.Assert().Len(reported, 2)
+}
+
+func (suite *ProblemsTestSuite) TestItReturnsTheLoadedConfig() {
+	envFile := filepath.Join(suite.dir, ".env")
+	content := []byte("PROBLEMS_HOST=localhost\nPROBLEMS_PORT=5432\n")
+	suite.Require().NoError(os.WriteFile(envFile, content, 0644))
+
+	appConfig := MustLoad[problemsAppConfig](WithDir(suite.dir))
+
+	suite.Assert().Equal(-1, suite.exitCode)
+	suite.Assert().Equal(
+		&problemsAppConfig{Database: problemsDatabaseConfig{Host: "localhost", Port: 5432}},
+		appConfig,
+	)
+}
+
+func TestProblemsSuite(t *testing.T) {
+	suite.Run(t, new(ProblemsTestSuite))
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SourceKind identifies the kind of source a configuration value came from.
+type SourceKind int
+
+const (
+	// SourceDefault means the key is not set, so the field holds the value chosen by the config
+	// code (a fallback in Populate or the zero value).
+	SourceDefault SourceKind = iota
+	// SourceProcessEnv means the value was already present in the process environment.
+	SourceProcessEnv
+	// SourceFile means the value was loaded from an env file.
+	SourceFile
+	// SourceFlag means the value was set from a command line flag via SetFromFlag.
+	SourceFlag
+)
+
+// String returns a human-readable name of the source kind.
+func (k SourceKind) String() string {
+	switch k {
+	case SourceProcessEnv:
+		return "process env"
+	case SourceFile:
+		return "file"
+	case SourceFlag:
+		return "flag"
+	default:
+		return "default"
+	}
+}
+
+// Origin describes where the value of an env key came from.
+// File and Line are only set when Source is SourceFile.
+type Origin struct {
+	Key    string
+	Source SourceKind
+	File   string
+	Line   int
+}
+
+// String returns the origin formatted as "file:line" for file sources or the source kind name.
+func (o Origin) String() string {
+	if o.Source == SourceFile {
+		return fmt.Sprintf("%s:%d", o.File, o.Line)
+	}
+
+	return o.Source.String()
+}
+
+// FieldOrigin associates a config field path (e.g. "Database.Host") with the origin of its value.
+type FieldOrigin struct {
+	Field  string
+	Origin Origin
+}
+
+// String returns the field origin formatted as "Database.Host: DB_HOST from .env:3".
+func (f FieldOrigin) String() string {
+	return fmt.Sprintf("%s: %s from %s", f.Field, f.Origin.Key, f.Origin)
+}
+
+// recordedOrigin is an origin along with the value that was set, used to detect values
+// overwritten after they were recorded.
+type recordedOrigin struct {
+	origin Origin
+	value  string
+}
+
+var origins = struct {
+	sync.RWMutex
+	byKey map[string]recordedOrigin
+}{byKey: make(map[string]recordedOrigin)}
+
+// recordOrigin remembers the origin of a value set in the process environment.
+func recordOrigin(origin Origin, value string) {
+	origins.Lock()
+	defer origins.Unlock()
+	origins.byKey[origin.Key] = recordedOrigin{origin: origin, value: value}
+}
+
+// OriginOf returns the origin of the current value of an env key.
+// Keys set by LoadEnvVars or SetFromFlag report the recorded source, other set keys are reported
+// as coming from the process environment and unset keys as defaults.
+func OriginOf(key string) Origin {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return Origin{Key: key, Source: SourceDefault}
+	}
+
+	origins.RLock()
+	recorded, found := origins.byKey[key]
+	origins.RUnlock()
+
+	if found && recorded.value == value {
+		return recorded.origin
+	}
+
+	return Origin{Key: key, Source: SourceProcessEnv}
+}
+
+// SetFromFlag sets an env variable from a command line flag value, overwriting any existing value,
+// and records the flag as its origin.
+func SetFromFlag(key string, value string) error {
+	if err := os.Setenv(key, value); err != nil {
+		return fmt.Errorf("failed to set env var %s from flag: %w", key, err)
+	}
+
+	recordOrigin(Origin{Key: key, Source: SourceFlag}, value)
+	return nil
+}
+
+// Explain reports the origin of every field in a config struct tree that declares its env
+// variable with an `env` tag. Fields are reported in declaration order with dotted paths, and the
+// fields of the elements of slices of structs populated from indexed env variables with their
+// index, e.g. "Upstreams[1].Host".
+func Explain(config interface{}) []FieldOrigin {
+	var result []FieldOrigin
+	visiting := make(map[uintptr]bool)
+	walk := func(path string, key string) {
+		result = append(result, FieldOrigin{Field: path, Origin: OriginOf(key)})
+	}
+	defaultDecoding.walkEnvFields(reflect.ValueOf(config), "", "", visiting, walk)
+
+	return result
+}
+
+// walkEnvFields calls fn for every exported field tagged with `env`, descending into nested
+// structs and into the elements of the slices of structs populated from indexed env variables.
+// The prefix, extended by the `envPrefix` tags of the nested struct fields, is prepended to the
+// keys. The pointers being visited are skipped, so pointer cycles do not recurse forever.
+func (c *CompositeConfig) walkEnvFields(
+	val reflect.Value,
+	path string,
+	prefix string,
+	visiting map[uintptr]bool,
+	fn func(path string, key string),
+) {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() || visiting[val.Pointer()] {
+			return
+		}
+		pointer := val.Pointer()
+		visiting[pointer] = true
+		defer delete(visiting, pointer)
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return
+	}
+
+	typ := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		fieldType := typ.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		fieldPath := fieldType.Name
+		if path != "" {
+			fieldPath = path + "." + fieldType.Name
+		}
+
+		key := envTagName(fieldType)
+		if key == "" {
+			fieldPrefix := prefix + fieldType.Tag.Get("envPrefix")
+			c.walkEnvFields(val.Field(i), fieldPath, fieldPrefix, visiting, fn)
+			continue
+		}
+
+		if !c.isIndexedField(fieldType) {
+			fn(fieldPath, prefix+key)
+			continue
+		}
+
+		elems := val.Field(i)
+		for index := 0; index < elems.Len(); index++ {
+			elemPath := fmt.Sprintf("%s[%d]", fieldPath, index)
+			elemPrefix := indexedKeyPrefix(prefix+key, index)
+			c.walkEnvFields(elems.Index(index), elemPath, elemPrefix, visiting, fn)
+		}
+	}
+}
+
+// envTagName returns the env variable name declared in the `env` tag of a field, ignoring options.
+func envTagName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+	return strings.TrimSpace(name)
+}
+
+// originAnnotation returns the " (KEY from origin)" suffix used by Debug for env tagged fields,
+// whose keys are prefixed by the given prefix.
+func originAnnotation(field reflect.StructField, prefix string) string {
+	key := envTagName(field)
+	if key == "" {
+		return ""
+	}
+	key = prefix + key
+
+	return fmt.Sprintf(" (%s from %s)", key, OriginOf(key))
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ProvenanceTestSuite struct {
+	suite.Suite
+}
+
+type provenanceDatabaseConfig struct {
+	Host     string `env:"PROVENANCE_DB_HOST"`
+	Password string `env:"PROVENANCE_DB_PASSWORD"`
+	Timeout  int
+}
+
+type provenanceAppConfig struct {
+	Database provenanceDatabaseConfig
+	Mode     string `env:"PROVENANCE_MODE"`
+	Name     string `env:"PROVENANCE_NAME"`
+}
+
+func (suite *ProvenanceTestSuite) TestItRecordsTheFileAndLineOfLoadedValues() {
+	unsetEnv(suite.T(), "PROVENANCE_DB_HOST", "PROVENANCE_MODE")
+	dir := suite.T().TempDir()
+	localFile := writeEnvFile(suite.T(), dir, ".env.test.local", "PROVENANCE_MODE=local\n")
+	baseFile := writeEnvFile(
+		suite.T(),
+		dir,
+		".env",
+		"# database\n\nexport PROVENANCE_DB_HOST=db\nPROVENANCE_MODE=base\n",
+	)
+
+	suite.Require().NoError(LoadEnvVars("test", dir))
+
+	suite.Assert().Equal(
+		Origin{Key: "PROVENANCE_DB_HOST", Source: SourceFile, File: baseFile, Line: 3},
+		OriginOf("PROVENANCE_DB_HOST"),
+	)
+	suite.Assert().Equal(
+		Origin{Key: "PROVENANCE_MODE", Source: SourceFile, File: localFile, Line: 1},
+		OriginOf("PROVENANCE_MODE"),
+	)
+	suite.Assert().Equal(baseFile+":3", OriginOf("PROVENANCE_DB_HOST").String())
+}
+
+func (suite *ProvenanceTestSuite) TestItReportsProcessEnvFlagAndDefaultOrigins() {
+	unsetEnv(suite.T(), "PROVENANCE_DB_HOST", "PROVENANCE_NAME")
+	suite.T().Setenv("PROVENANCE_MODE", "exported")
+	dir := suite.T().TempDir()
+	writeEnvFile(suite.T(), dir, ".env", "PROVENANCE_MODE=file\n")
+
+	suite.Require().NoError(LoadEnvVars("test", dir))
+	suite.Require().NoError(SetFromFlag("PROVENANCE_NAME", "from-flag"))
+
+	suite.Assert().Equal(SourceProcessEnv, OriginOf("PROVENANCE_MODE").Source)
+	suite.Assert().Equal("exported", os.Getenv("PROVENANCE_MODE"))
+	suite.Assert().Equal(SourceFlag, OriginOf("PROVENANCE_NAME").Source)
+	suite.Assert().Equal(SourceDefault, OriginOf("PROVENANCE_DB_HOST").Source)
+}
+
+func (suite *ProvenanceTestSuite) TestItReportsValuesOverwrittenAfterLoadingAsProcessEnv() {
+	unsetEnv(suite.T(), "PROVENANCE_MODE")
+	dir := suite.T().TempDir()
+	writeEnvFile(suite.T(), dir, ".env", "PROVENANCE_MODE=file\n")
+
+	suite.Require().NoError(LoadEnvVars("test", dir))
+	suite.T().Setenv("PROVENANCE_MODE", "changed")
+
+	suite.Assert().Equal(SourceProcessEnv, OriginOf("PROVENANCE_MODE").Source)
+}
+
+func (suite *ProvenanceTestSuite) TestItCanExplainConfigFields() {
+	unsetEnv(suite.T(), "PROVENANCE_DB_HOST", "PROVENANCE_DB_PASSWORD", "PROVENANCE_NAME")
+	suite.T().Setenv("PROVENANCE_MODE", "exported")
+	dir := suite.T().TempDir()
+	envFile := writeEnvFile(suite.T(), dir, ".env", "PROVENANCE_DB_HOST=db\n")
+	suite.Require().NoError(LoadEnvVars("test", dir))
+
+	result := Explain(&provenanceAppConfig{})
+
+	suite.Assert().Equal(
+		[]FieldOrigin{
+			{
+				Field:  "Database.Host",
+				Origin: Origin{Key: "PROVENANCE_DB_HOST", Source: SourceFile, File: envFile, Line: 1},
+			},
+			{
+				Field:  "Database.Password",
+				Origin: Origin{Key: "PROVENANCE_DB_PASSWORD", Source: SourceDefault},
+			},
+			{Field: "Mode", Origin: Origin{Key: "PROVENANCE_MODE", Source: SourceProcessEnv}},
+			{Field: "Name", Origin: Origin{Key: "PROVENANCE_NAME", Source: SourceDefault}},
+		},
+		result,
+	)
+	suite.Assert().Equal(
+		"Database.Host: PROVENANCE_DB_HOST from "+envFile+":1",
+		result[0].String(),
+	)
+}
+
+func (suite *ProvenanceTestSuite) TestItExplainsTheElementsOfIndexedStructSlices() {
+	unsetEnv(suite.T(), "PROVENANCE_UPSTREAM_0_PROVENANCE_DB_HOST")
+	suite.T().Setenv("PROVENANCE_UPSTREAM_1_PROVENANCE_DB_HOST", "b.local")
+	appConfig := struct {
+		Upstreams []provenanceDatabaseConfig `env:"PROVENANCE_UPSTREAM"`
+	}{Upstreams: make([]provenanceDatabaseConfig, 2)}
+
+	result := Explain(&appConfig)
+
+	suite.Require().Len(result, 4)
+	suite.Assert().Equal(
+		FieldOrigin{
+			Field:  "Upstreams[0].Host",
+			Origin: Origin{Key: "PROVENANCE_UPSTREAM_0_PROVENANCE_DB_HOST", Source: SourceDefault},
+		},
+		result[0],
+	)
+	suite.Assert().Equal(SourceProcessEnv, result[2].Origin.Source)
+	suite.Assert().Equal("Upstreams[1].Password", result[3].Field)
+	suite.Assert().Equal("PROVENANCE_UPSTREAM_1_PROVENANCE_DB_PASSWORD", result[3].Origin.Key)
+
+	appConfig.Upstreams[1].Host = "b.local"
+	output := Debug(appConfig, nil)
+	suite.Assert().Contains(output, "Upstreams: \n")
+	suite.Assert().Contains(
+		output,
+		"Host: b.local (PROVENANCE_UPSTREAM_1_PROVENANCE_DB_HOST from process env)",
+	)
+}
+
+func (suite *ProvenanceTestSuite) TestItExplainsSelfReferencingConfigs() {
+	unsetEnv(suite.T(), "PROVENANCE_NODE_NAME")
+	type node struct {
+		Name   string `env:"PROVENANCE_NODE_NAME"`
+		Parent *node
+	}
+	root := &node{}
+	root.Parent = root
+
+	result := Explain(root)
+
+	suite.Assert().Equal(
+		[]FieldOrigin{
+			{Field: "Name", Origin: Origin{Key: "PROVENANCE_NODE_NAME", Source: SourceDefault}},
+		},
+		result,
+	)
+}
+
+func (suite *ProvenanceTestSuite) TestItAnnotatesDebugOutputWithOrigins() {
+	unsetEnv(suite.T(), "PROVENANCE_DB_HOST", "PROVENANCE_DB_PASSWORD", "PROVENANCE_NAME")
+	suite.T().Setenv("PROVENANCE_MODE", "exported")
+
+	result := Debug(
+		provenanceAppConfig{
+			Database: provenanceDatabaseConfig{Host: "db", Password: "secret", Timeout: 5},
+			Mode:     "exported",
+		},
+		[]string{"password"},
+	)
+
+	suite.Assert().Contains(result, "Host: db (PROVENANCE_DB_HOST from default)")
+	suite.Assert().Contains(result, "Password: s****t (PROVENANCE_DB_PASSWORD from default)")
+	suite.Assert().Contains(result, "Timeout: 5\n")
+	suite.Assert().Contains(result, "Mode: exported (PROVENANCE_MODE from process env)")
+}
+
+func TestProvenanceSuite(t *testing.T) {
+	suite.Run(t, new(ProvenanceTestSuite))
+}
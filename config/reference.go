@@ -0,0 +1,124 @@
+package config
+
+import (
+	"html/template"
+	"io"
+	"strings"
+)
+
+// referenceRow is a row of the settings reference table.
+type referenceRow struct {
+	Env         string
+	Type        string
+	Default     string
+	Required    string
+	Validation  string
+	Description string
+}
+
+var htmlReferenceTemplate = template.Must(template.New("reference").Parse(`<table>
+  <thead>
+    <tr>
+      <th>Env var</th>
+      <th>Type</th>
+      <th>Default</th>
+      <th>Required</th>
+      <th>Validation</th>
+      <th>Description</th>
+    </tr>
+  </thead>
+  <tbody>
+{{- range .}}
+    <tr>
+      <td><code>{{.Env}}</code></td>
+      <td><code>{{.Type}}</code></td>
+      <td>{{if .Default}}<code>{{.Default}}</code>{{end}}</td>
+      <td>{{.Required}}</td>
+      <td>{{.Validation}}</td>
+      <td>{{.Description}}</td>
+    </tr>
+{{- end}}
+  </tbody>
+</table>
+`))
+
+// WriteMarkdownReference writes a markdown table documenting every field spec: env var name, Go
+// type, default, whether it is required, validation rules and description. Defaults of sensitive
+// fields (whose env or field name matches one of the sensitiveKeys) are omitted.
+func WriteMarkdownReference(w io.Writer, specs []FieldSpec, sensitiveKeys []string) error {
+	var builder strings.Builder
+	builder.WriteString("| Env var | Type | Default | Required | Validation | Description |\n")
+	builder.WriteString("|---|---|---|---|---|---|\n")
+
+	for _, row := range referenceRows(specs, sensitiveKeys) {
+		cells := []string{
+			markdownCode(row.Env),
+			markdownCode(row.Type),
+			markdownCode(row.Default),
+			row.Required,
+			markdownCell(row.Validation),
+			markdownCell(row.Description),
+		}
+		builder.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// WriteHTMLReference writes the same reference table as WriteMarkdownReference as an HTML table.
+func WriteHTMLReference(w io.Writer, specs []FieldSpec, sensitiveKeys []string) error {
+	return htmlReferenceTemplate.Execute(w, referenceRows(specs, sensitiveKeys))
+}
+
+// referenceRows converts field specs to reference table rows.
+func referenceRows(specs []FieldSpec, sensitiveKeys []string) []referenceRow {
+	rows := make([]referenceRow, 0, len(specs))
+	for _, spec := range specs {
+		row := referenceRow{
+			Env:         spec.Env,
+			Type:        spec.Type,
+			Default:     spec.Default,
+			Required:    "no",
+			Validation:  validationRules(spec.Validate),
+			Description: spec.Description,
+		}
+
+		if spec.Required {
+			row.Required = "yes"
+		}
+		if isSensitiveSpec(spec, sensitiveKeys) {
+			row.Default = ""
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// validationRules returns the validator rules other than "required", which has its own column.
+func validationRules(validate string) string {
+	var rules []string
+	for _, rule := range strings.Split(validate, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" && rule != "required" {
+			rules = append(rules, rule)
+		}
+	}
+
+	return strings.Join(rules, ", ")
+}
+
+// markdownCode formats a non-empty value as inline code in a table cell.
+func markdownCode(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return "`" + markdownCell(value) + "`"
+}
+
+// markdownCell escapes a value so it fits in a single markdown table cell.
+func markdownCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace(value)
+}
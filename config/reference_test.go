@@ -0,0 +1,69 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ReferenceTestSuite struct {
+	suite.Suite
+}
+
+var referenceSpecs = []FieldSpec{
+	{
+		Path:        "Database.Host",
+		Env:         "DB_HOST",
+		Type:        "string",
+		Default:     "localhost",
+		Description: "Database host | primary",
+		Validate:    "required,hostname|ip",
+		Required:    true,
+	},
+	{Path: "Database.Password", Env: "DB_PASSWORD", Type: "string", Default: "dev"},
+	{Path: "Timeout", Env: "TIMEOUT", Type: "time.Duration", Description: "Request\ntimeout"},
+}
+
+func (suite *ReferenceTestSuite) TestItWritesAMarkdownReference() {
+	var output bytes.Buffer
+
+	err := WriteMarkdownReference(&output, referenceSpecs, []string{"password"})
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(
+		"| Env var | Type | Default | Required | Validation | Description |\n"+
+			"|---|---|---|---|---|---|\n"+
+			"| `DB_HOST` | `string` | `localhost` | yes | hostname\\|ip | Database host \\| primary |\n"+
+			"| `DB_PASSWORD` | `string` |  | no |  |  |\n"+
+			"| `TIMEOUT` | `time.Duration` |  | no |  | Request<br>timeout |\n",
+		output.String(),
+	)
+}
+
+func (suite *ReferenceTestSuite) TestItWritesAnHTMLReference() {
+	var output bytes.Buffer
+	specs := append(referenceSpecs, FieldSpec{Env: "HTML", Type: "string", Description: "<b>x</b>"})
+
+	err := WriteHTMLReference(&output, specs, []string{"password"})
+
+	suite.Require().NoError(err)
+	suite.Assert().Contains(
+		output.String(),
+		"      <td><code>DB_HOST</code></td>\n"+
+			"      <td><code>string</code></td>\n"+
+			"      <td><code>localhost</code></td>\n"+
+			"      <td>yes</td>\n"+
+			"      <td>hostname|ip</td>\n"+
+			"      <td>Database host | primary</td>\n",
+	)
+	suite.Assert().Contains(
+		output.String(),
+		"<td><code>DB_PASSWORD</code></td>\n      <td><code>string</code></td>\n      <td></td>",
+	)
+	suite.Assert().Contains(output.String(), "<td>&lt;b&gt;x&lt;/b&gt;</td>")
+}
+
+func TestReferenceSuite(t *testing.T) {
+	suite.Run(t, new(ReferenceTestSuite))
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The value types of an EnvSchema.
+const (
+	SchemaString   = "string"
+	SchemaInt      = "int"
+	SchemaUint     = "uint"
+	SchemaFloat    = "float"
+	SchemaBool     = "bool"
+	SchemaDuration = "duration"
+	SchemaURL      = "url"
+)
+
+// schemaTypes maps the value types of an EnvSchema to the functions checking their values.
+var schemaTypes = map[string]func(string) error{
+	SchemaString: func(string) error { return nil },
+	SchemaInt: func(value string) error {
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err
+	},
+	SchemaUint: func(value string) error {
+		_, err := strconv.ParseUint(value, 10, 64)
+		return err
+	},
+	SchemaFloat: func(value string) error {
+		_, err := strconv.ParseFloat(value, 64)
+		return err
+	},
+	SchemaBool: func(value string) error {
+		_, err := strconv.ParseBool(value)
+		return err
+	},
+	SchemaDuration: func(value string) error {
+		_, err := time.ParseDuration(value)
+		return err
+	},
+	SchemaURL: func(value string) error {
+		parsed, err := url.Parse(value)
+		if err == nil && parsed.Scheme == "" {
+			err = fmt.Errorf("missing scheme in %q", value)
+		}
+		return err
+	},
+}
+
+// EnvSchema declares the env vars of a service, so resolved env values can be checked without
+// compiling the service, e.g. by `goconfig check -schema`. It is read from YAML or JSON files:
+//
+//	vars:
+//	  - name: DB_PORT
+//	    type: int
+//	    required: true
+//	    default: "5432"
+//	  - name: APP_MODE
+//	    enum: [dev, prod]
+type EnvSchema struct {
+	Vars []EnvVarSchema `json:"vars" yaml:"vars"`
+}
+
+// EnvVarSchema declares an env var of an EnvSchema.
+type EnvVarSchema struct {
+	Name string `json:"name" yaml:"name"`
+	// Type is one of the Schema* value types. Defaults to SchemaString.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Required makes missing and empty values invalid, unless a Default is declared.
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+	// Default is the value used by the service when the env var is not set.
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+	// Pattern is a regular expression the whole value must match.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Enum lists the allowed values.
+	Enum        []string `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// SchemaViolation describes an env value not matching its EnvVarSchema.
+type SchemaViolation struct {
+	Key    string
+	Reason string
+}
+
+// SchemaError lists the violations found by EnvSchema.Validate.
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+// Error lists the invalid keys along with the reason.
+func (e *SchemaError) Error() string {
+	violations := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		violations = append(violations, violation.Key+": "+violation.Reason)
+	}
+
+	return "env schema violations: " + strings.Join(violations, "; ")
+}
+
+// ParseEnvSchema parses an EnvSchema from YAML or JSON and checks its declarations.
+func ParseEnvSchema(data []byte) (*EnvSchema, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	schema := &EnvSchema{}
+	if err := decoder.Decode(schema); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse env schema: %w", err)
+	}
+
+	for _, variable := range schema.Vars {
+		if !isValidEnvKey(variable.Name) {
+			return nil, fmt.Errorf("invalid env schema var name %q", variable.Name)
+		}
+		if _, ok := schemaTypes[variable.schemaType()]; !ok {
+			return nil, fmt.Errorf(
+				"unknown type %q of env schema var %s",
+				variable.Type,
+				variable.Name,
+			)
+		}
+		if _, err := regexp.Compile(variable.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern of env schema var %s: %w", variable.Name, err)
+		}
+	}
+
+	return schema, nil
+}
+
+// EnvSchemaFromSpecs returns the EnvSchema of the field specs of a config struct tree, e.g.
+// EnvSchemaFromSpecs(Describe(&AppConfig{})). Go types are mapped to the closest schema type and
+// the values of "oneof" validator rules are declared as Enum.
+func EnvSchemaFromSpecs(specs []FieldSpec) *EnvSchema {
+	schema := &EnvSchema{}
+	for _, spec := range specs {
+		variable := EnvVarSchema{
+			Name:        spec.Env,
+			Type:        specSchemaType(spec.Type),
+			Required:    spec.Required,
+			Default:     spec.Default,
+			Description: spec.Description,
+		}
+
+		for _, rule := range strings.Split(spec.Validate, ",") {
+			if name, param, _ := strings.Cut(strings.TrimSpace(rule), "="); name == "oneof" {
+				variable.Enum = strings.Fields(param)
+			}
+		}
+
+		schema.Vars = append(schema.Vars, variable)
+	}
+
+	return schema
+}
+
+// WriteEnvSchema writes an EnvSchema as YAML.
+func WriteEnvSchema(w io.Writer, schema *EnvSchema) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(schema); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// Validate checks resolved env values, by key, against the schema and returns a *SchemaError
+// listing every violation. Values missing or empty are valid unless required without a default.
+func (s *EnvSchema) Validate(values map[string]string) error {
+	var violations []SchemaViolation
+	for _, variable := range s.Vars {
+		if reason := variable.check(values[variable.Name]); reason != "" {
+			violations = append(violations, SchemaViolation{Key: variable.Name, Reason: reason})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &SchemaError{Violations: violations}
+	}
+
+	return nil
+}
+
+// check returns the reason why a value does not match the declaration, or "" if it does. The
+// reason does not quote the value, which may be a secret printed to CI logs.
+func (v EnvVarSchema) check(value string) string {
+	if value == "" {
+		if v.Required && v.Default == "" {
+			return "required but not set"
+		}
+		return ""
+	}
+
+	checkType, ok := schemaTypes[v.schemaType()]
+	if !ok {
+		return fmt.Sprintf("unknown type %q", v.Type)
+	}
+	if err := checkType(value); err != nil {
+		return fmt.Sprintf("invalid %s value", v.schemaType())
+	}
+
+	if v.Pattern != "" {
+		pattern, err := regexp.Compile("^(?:" + v.Pattern + ")$")
+		if err != nil {
+			return fmt.Sprintf("invalid pattern %q", v.Pattern)
+		}
+		if !pattern.MatchString(value) {
+			return fmt.Sprintf("value does not match pattern %q", v.Pattern)
+		}
+	}
+
+	if len(v.Enum) > 0 && !slices.Contains(v.Enum, value) {
+		return "value is not one of " + strings.Join(v.Enum, ", ")
+	}
+
+	return ""
+}
+
+// schemaType returns the value type of the declaration.
+func (v EnvVarSchema) schemaType() string {
+	if v.Type == "" {
+		return SchemaString
+	}
+
+	return v.Type
+}
+
+// specSchemaType maps the Go type of a field spec to a schema value type. Other types, e.g.
+// interface {} or named types like internal.Mode, are declared as strings.
+func specSchemaType(goType string) string {
+	switch strings.TrimLeft(goType, "*") {
+	case "bool":
+		return SchemaBool
+	case "time.Duration":
+		return SchemaDuration
+	case "url.URL":
+		return SchemaURL
+	case "int", "int8", "int16", "int32", "int64":
+		return SchemaInt
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return SchemaUint
+	case "float32", "float64":
+		return SchemaFloat
+	default:
+		return SchemaString
+	}
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SchemaTestSuite struct {
+	suite.Suite
+}
+
+const yamlEnvSchema = `vars:
+  - name: DB_HOST
+    required: true
+    pattern: "[a-z.]+"
+  - name: DB_PORT
+    type: int
+    required: true
+    default: "5432"
+  - name: APP_MODE
+    enum: [dev, prod]
+  - name: TIMEOUT
+    type: duration
+  - name: API_URL
+    type: url
+`
+
+func (suite *SchemaTestSuite) TestItValidatesValuesAgainstASchema() {
+	schema, err := ParseEnvSchema([]byte(yamlEnvSchema))
+	suite.Require().NoError(err)
+
+	testCases := []struct {
+		name       string
+		values     map[string]string
+		violations []SchemaViolation
+	}{
+		{
+			name:   "valid values",
+			values: map[string]string{"DB_HOST": "db.local", "APP_MODE": "prod", "TIMEOUT": "5s"},
+		},
+		{
+			name:       "missing required value",
+			values:     map[string]string{"DB_PORT": "5432"},
+			violations: []SchemaViolation{{Key: "DB_HOST", Reason: "required but not set"}},
+		},
+		{
+			name: "invalid values",
+			values: map[string]string{
+				"DB_HOST":  "DB",
+				"DB_PORT":  "port",
+				"APP_MODE": "staging",
+				"TIMEOUT":  "5",
+				"API_URL":  "localhost",
+			},
+			violations: []SchemaViolation{
+				{Key: "DB_HOST", Reason: `value does not match pattern "[a-z.]+"`},
+				{Key: "DB_PORT", Reason: "invalid int value"},
+				{Key: "APP_MODE", Reason: "value is not one of dev, prod"},
+				{Key: "TIMEOUT", Reason: "invalid duration value"},
+				{Key: "API_URL", Reason: "invalid url value"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			err := schema.Validate(testCase.values)
+
+			if testCase.violations == nil {
+				suite.Assert().NoError(err)
+				return
+			}
+			var schemaErr *SchemaError
+			suite.Require().True(errors.As(err, &schemaErr))
+			suite.Assert().Equal(testCase.violations, schemaErr.Violations)
+		})
+	}
+}
+
+func (suite *SchemaTestSuite) TestItParsesJSONSchemas() {
+	schema, err := ParseEnvSchema([]byte(`{"vars": [{"name": "DB_PORT", "type": "int"}]}`))
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(&EnvSchema{Vars: []EnvVarSchema{{Name: "DB_PORT", Type: "int"}}}, schema)
+	suite.Assert().EqualError(
+		schema.Validate(map[string]string{"DB_PORT": "x"}),
+		"env schema violations: DB_PORT: invalid int value",
+	)
+}
+
+func (suite *SchemaTestSuite) TestItRejectsInvalidSchemas() {
+	testCases := []struct {
+		name    string
+		schema  string
+		message string
+	}{
+		{"unknown field", "vars:\n  - name: A\n    kind: int\n", "field kind not found"},
+		{"invalid name", "vars:\n  - name: A B\n", `invalid env schema var name "A B"`},
+		{"unknown type", "vars:\n  - name: A\n    type: ip\n", `unknown type "ip"`},
+		{"invalid pattern", "vars:\n  - name: A\n    pattern: \"[\"\n", "invalid pattern"},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			_, err := ParseEnvSchema([]byte(testCase.schema))
+
+			suite.Require().Error(err)
+			suite.Assert().Contains(err.Error(), testCase.message)
+		})
+	}
+}
+
+func (suite *SchemaTestSuite) TestItGeneratesASchemaFromAConfigStruct() {
+	var output bytes.Buffer
+	specs := []FieldSpec{
+		{Env: "APP_MODE", Type: "string", Default: "dev", Validate: "oneof=dev prod"},
+		{Env: "DB_PORT", Type: "int", Required: true, Description: "Database port"},
+		{Env: "TIMEOUT", Type: "*time.Duration"},
+	}
+
+	err := WriteEnvSchema(&output, EnvSchemaFromSpecs(specs))
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(
+		"vars:\n"+
+			"  - name: APP_MODE\n"+
+			"    type: string\n"+
+			"    default: dev\n"+
+			"    enum:\n"+
+			"      - dev\n"+
+			"      - prod\n"+
+			"  - name: DB_PORT\n"+
+			"    type: int\n"+
+			"    required: true\n"+
+			"    description: Database port\n"+
+			"  - name: TIMEOUT\n"+
+			"    type: duration\n",
+		output.String(),
+	)
+	schema, err := ParseEnvSchema(output.Bytes())
+	suite.Require().NoError(err)
+	suite.Assert().Equal(EnvSchemaFromSpecs(specs), schema)
+}
+
+func (suite *SchemaTestSuite) TestItMapsOnlyTheNumericGoTypesToNumericSchemaTypes() {
+	testCases := map[string]string{
+		"int":           SchemaInt,
+		"*int64":        SchemaInt,
+		"uint8":         SchemaUint,
+		"float32":       SchemaFloat,
+		"interface {}":  SchemaString,
+		"internal.Mode": SchemaString,
+		"uintptr":       SchemaString,
+		"floatFlag":     SchemaString,
+		"[]int":         SchemaString,
+	}
+
+	for goType, expected := range testCases {
+		suite.Assert().Equal(expected, specSchemaType(goType), goType)
+	}
+}
+
+func TestSchemaSuite(t *testing.T) {
+	suite.Run(t, new(SchemaTestSuite))
+}
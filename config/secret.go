@@ -0,0 +1,27 @@
+package config
+
+// secretMask replaces the value of a Secret when it is printed.
+const secretMask = "****"
+
+// Secret holds sensitive bytes, e.g. a signing key decoded with the "base64" or "hex" option of
+// an `env` tag. It is masked when printed or encoded, so it does not leak through logs or Debug.
+type Secret []byte
+
+// String returns a fixed mask, or "" for an empty secret, instead of the secret bytes.
+func (s Secret) String() string {
+	if len(s) == 0 {
+		return ""
+	}
+
+	return secretMask
+}
+
+// GoString masks the secret bytes for the %#v format.
+func (s Secret) GoString() string {
+	return "config.Secret(" + s.String() + ")"
+}
+
+// MarshalText masks the secret bytes for encoding/json and other text encoders.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SecretTestSuite struct {
+	suite.Suite
+}
+
+func (suite *SecretTestSuite) TestItMasksSecretsWhenPrinted() {
+	secret := Secret("signing-key")
+
+	suite.Assert().Equal("****", secret.String())
+	suite.Assert().Equal("****", fmt.Sprintf("%v", secret))
+	suite.Assert().Equal("****", fmt.Sprintf("%s", secret))
+	suite.Assert().Equal("config.Secret(****)", fmt.Sprintf("%#v", secret))
+	suite.Assert().Equal("", Secret(nil).String())
+}
+
+func (suite *SecretTestSuite) TestItMasksSecretsWhenEncoded() {
+	encoded, err := json.Marshal(struct{ Key Secret }{Key: Secret("signing-key")})
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(`{"Key":"****"}`, string(encoded))
+}
+
+func (suite *SecretTestSuite) TestItMasksSecretsInDebugOutput() {
+	output := Debug(struct{ Key Secret }{Key: Secret("signing-key")}, nil)
+
+	suite.Assert().Equal("Config Debug Output:\nKey: ****\n", output)
+}
+
+type secretDatabaseConfig struct {
+	Host     string
+	Password string
+}
+
+// String formats the config without masking its password.
+func (c secretDatabaseConfig) String() string {
+	return "db(" + c.Host + ":" + c.Password + ")"
+}
+
+func (suite *SecretTestSuite) TestItOnlyPrintsValuesFormattingThemselvesAsScalars() {
+	config := struct {
+		Database  secretDatabaseConfig
+		CreatedAt time.Time
+	}{
+		Database:  secretDatabaseConfig{Host: "h", Password: "hunter2"},
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	output := Debug(config, []string{"pass"})
+
+	suite.Assert().Equal(
+		"Config Debug Output:\n"+
+			"Database: \n  Host: h\n  Password: h*****2\n"+
+			"CreatedAt: 2026-01-02 03:04:05 +0000 UTC\n",
+		output,
+	)
+}
+
+func TestSecretSuite(t *testing.T) {
+	suite.Run(t, new(SecretTestSuite))
+}
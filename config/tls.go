@@ -0,0 +1,120 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// tlsVersions maps the accepted TLS.MinVersion values to the crypto/tls versions. TLS 1.0 and 1.1
+// are deprecated (RFC 8996) and rejected.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLS is a ready-made config of the TLS settings of a server or client. Its fields are populated
+// from standardized env vars, which are usually prefixed with the `envPrefix` tag of the field
+// holding it, so a service can have several of them:
+//
+//	type AppConfig struct {
+//		Server   config.TLS `envPrefix:"SERVER_"`   // SERVER_TLS_CERT_FILE, ...
+//		Upstream config.TLS `envPrefix:"UPSTREAM_"` // UPSTREAM_TLS_CA_FILE, ...
+//	}
+//
+// Its Populate method checks that the files exist and parse, so TLSConfig cannot fail.
+type TLS struct {
+	// CertFile and KeyFile are the paths of the PEM encoded certificate chain and private key.
+	// They must be set together.
+	CertFile string `env:"TLS_CERT_FILE" desc:"PEM certificate chain file"`
+	KeyFile  string `env:"TLS_KEY_FILE" desc:"PEM private key file"`
+	// CAFile is the path of the PEM encoded certificates trusted to verify the peers.
+	CAFile string `env:"TLS_CA_FILE" desc:"PEM CA certificates file"`
+	// MinVersion is the minimum TLS version: 1.2 or 1.3.
+	MinVersion string `env:"TLS_MIN_VERSION" default:"1.2" desc:"Minimum TLS version"`
+	// ServerName is the host name the clients verify the server certificate against.
+	ServerName string `env:"TLS_SERVER_NAME" desc:"Expected server host name"`
+	// RequireClientCert makes servers require and verify client certificates against CAFile.
+	RequireClientCert bool `env:"TLS_REQUIRE_CLIENT_CERT" desc:"Require client certificates"`
+
+	certificate *tls.Certificate
+	caPool      *x509.CertPool
+	minVersion  uint16
+}
+
+// Populate checks the TLS settings, and loads the key pair and the CA certificates.
+func (t *TLS) Populate() error {
+	t.certificate, t.caPool, t.minVersion = nil, nil, 0
+
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return errors.New("TLS cert file and key file must be set together")
+	}
+	if t.RequireClientCert && t.CAFile == "" {
+		return errors.New("TLS CA file is required to verify client certificates")
+	}
+
+	if t.MinVersion != "" {
+		version, ok := tlsVersions[t.MinVersion]
+		if !ok {
+			return fmt.Errorf(
+				"invalid TLS min version %q, expected 1.2 or 1.3",
+				t.MinVersion,
+			)
+		}
+		t.minVersion = version
+	}
+
+	if t.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS key pair %s: %w", t.CertFile, err)
+		}
+		t.certificate = &certificate
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read TLS CA file %s: %w", t.CAFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no PEM certificates found in TLS CA file %s", t.CAFile)
+		}
+		t.caPool = pool
+	}
+
+	return nil
+}
+
+// Enabled reports whether a certificate is configured, e.g. to choose between ListenAndServeTLS
+// and ListenAndServe.
+func (t *TLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// TLSConfig builds a *tls.Config from the settings loaded by Populate. It can be used by servers
+// (the CA certificates verify the client certificates) and by clients (the CA certificates verify
+// the server certificate). MinVersion defaults to TLS 1.2.
+func (t *TLS) TLSConfig() *tls.Config {
+	tlsConfig := &tls.Config{
+		MinVersion: t.minVersion,
+		ServerName: t.ServerName,
+		RootCAs:    t.caPool,
+		ClientCAs:  t.caPool,
+	}
+	if tlsConfig.MinVersion == 0 {
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+	if t.certificate != nil {
+		tlsConfig.Certificates = []tls.Certificate{*t.certificate}
+	}
+	if t.RequireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig
+}
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TLSTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+type tlsAppConfig struct {
+	Server   TLS `envPrefix:"SERVER_"`
+	Upstream TLS `envPrefix:"UPSTREAM_"`
+}
+
+func (suite *TLSTestSuite) SetupTest() {
+	for _, prefix := range []string{"SERVER_", "UPSTREAM_"} {
+		for _, key := range []string{
+			"TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_CA_FILE", "TLS_MIN_VERSION",
+			"TLS_SERVER_NAME", "TLS_REQUIRE_CLIENT_CERT",
+		} {
+			suite.T().Setenv(prefix+key, "")
+			_ = os.Unsetenv(prefix + key)
+		}
+	}
+
+	suite.dir = suite.T().TempDir()
+	suite.writeCertificates()
+}
+
+// writeCertificates writes a CA certificate (ca.pem) and a leaf key pair it signed (cert.pem and
+// key.pem) to the temp dir.
+func (suite *TLSTestSuite) writeCertificates() {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(
+		rand.Reader,
+		caTemplate,
+		caTemplate,
+		&caKey.PublicKey,
+		caKey,
+	)
+	suite.Require().NoError(err)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	leafDER, err := x509.CreateCertificate(
+		rand.Reader,
+		leafTemplate,
+		caTemplate,
+		&leafKey.PublicKey,
+		caKey,
+	)
+	suite.Require().NoError(err)
+	leafKeyDER, err := x509.MarshalECPrivateKey(leafKey)
+	suite.Require().NoError(err)
+
+	suite.writePEM("ca.pem", "CERTIFICATE", caDER)
+	suite.writePEM("cert.pem", "CERTIFICATE", leafDER)
+	suite.writePEM("key.pem", "EC PRIVATE KEY", leafKeyDER)
+}
+
+func (suite *TLSTestSuite) writePEM(name string, blockType string, der []byte) {
+	content := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	suite.Require().NoError(os.WriteFile(filepath.Join(suite.dir, name), content, 0600))
+}
+
+func (suite *TLSTestSuite) path(name string) string {
+	return filepath.Join(suite.dir, name)
+}
+
+func (suite *TLSTestSuite) TestItLoadsPrefixedTLSSettings() {
+	suite.T().Setenv("SERVER_TLS_CERT_FILE", suite.path("cert.pem"))
+	suite.T().Setenv("SERVER_TLS_KEY_FILE", suite.path("key.pem"))
+	suite.T().Setenv("SERVER_TLS_CA_FILE", suite.path("ca.pem"))
+	suite.T().Setenv("SERVER_TLS_REQUIRE_CLIENT_CERT", "true")
+	suite.T().Setenv("UPSTREAM_TLS_CA_FILE", suite.path("ca.pem"))
+	suite.T().Setenv("UPSTREAM_TLS_MIN_VERSION", "1.3")
+	suite.T().Setenv("UPSTREAM_TLS_SERVER_NAME", "localhost")
+
+	appConfig, err := Load[tlsAppConfig](WithDir(suite.dir))
+
+	suite.Require().NoError(err)
+	suite.Assert().True(appConfig.Server.Enabled())
+	suite.Assert().False(appConfig.Upstream.Enabled())
+
+	server := appConfig.Server.TLSConfig()
+	suite.Assert().Equal(uint16(tls.VersionTLS12), server.MinVersion)
+	suite.Assert().Len(server.Certificates, 1)
+	suite.Assert().NotNil(server.ClientCAs)
+	suite.Assert().Equal(tls.RequireAndVerifyClientCert, server.ClientAuth)
+
+	upstream := appConfig.Upstream.TLSConfig()
+	suite.Assert().Equal(uint16(tls.VersionTLS13), upstream.MinVersion)
+	suite.Assert().Equal("localhost", upstream.ServerName)
+	suite.Assert().Empty(upstream.Certificates)
+	suite.Assert().Equal(tls.NoClientCert, upstream.ClientAuth)
+
+	leaf, err := x509.ParseCertificate(server.Certificates[0].Certificate[0])
+	suite.Require().NoError(err)
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: upstream.RootCAs})
+	suite.Assert().NoError(err, "the upstream CA file verifies the server certificate")
+}
+
+func (suite *TLSTestSuite) TestItDescribesThePrefixedEnvVars() {
+	var keys []string
+	for _, spec := range Describe(&tlsAppConfig{}) {
+		keys = append(keys, spec.Env)
+	}
+
+	suite.Assert().Contains(keys, "SERVER_TLS_CERT_FILE")
+	suite.Assert().Contains(keys, "UPSTREAM_TLS_MIN_VERSION")
+	suite.Assert().Len(keys, 12)
+}
+
+func (suite *TLSTestSuite) TestItRejectsInvalidSettings() {
+	suite.Require().NoError(os.WriteFile(suite.path("invalid.pem"), []byte("not a PEM"), 0600))
+
+	testCases := []struct {
+		name    string
+		config  TLS
+		message string
+	}{
+		{
+			"cert without key",
+			TLS{CertFile: suite.path("cert.pem")},
+			"TLS cert file and key file must be set together",
+		},
+		{
+			"missing cert file",
+			TLS{CertFile: suite.path("missing.pem"), KeyFile: suite.path("key.pem")},
+			"failed to load TLS key pair " + suite.path("missing.pem") + ": open",
+		},
+		{
+			"unparseable key pair",
+			TLS{CertFile: suite.path("invalid.pem"), KeyFile: suite.path("key.pem")},
+			"failed to load TLS key pair",
+		},
+		{
+			"mismatched key pair",
+			TLS{CertFile: suite.path("ca.pem"), KeyFile: suite.path("key.pem")},
+			"failed to load TLS key pair",
+		},
+		{
+			"missing CA file",
+			TLS{CAFile: suite.path("missing.pem")},
+			"failed to read TLS CA file " + suite.path("missing.pem") + ": open",
+		},
+		{
+			"unreadable CA file",
+			TLS{CAFile: suite.dir},
+			"failed to read TLS CA file " + suite.dir + ": read",
+		},
+		{
+			"unparseable CA file",
+			TLS{CAFile: suite.path("invalid.pem")},
+			"no PEM certificates found in TLS CA file",
+		},
+		{
+			"client certs without CA file",
+			TLS{RequireClientCert: true},
+			"TLS CA file is required",
+		},
+		{
+			"invalid min version",
+			TLS{MinVersion: "1.4"},
+			`invalid TLS min version "1.4"`,
+		},
+		{
+			"deprecated min version",
+			TLS{MinVersion: "1.1"},
+			`invalid TLS min version "1.1", expected 1.2 or 1.3`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			err := testCase.config.Populate()
+
+			suite.Require().Error(err)
+			suite.Assert().Contains(err.Error(), testCase.message)
+		})
+	}
+}
+
+func (suite *TLSTestSuite) TestItReportsTheFieldOfInvalidSettings() {
+	suite.T().Setenv("UPSTREAM_TLS_CA_FILE", suite.path("missing.pem"))
+
+	_, err := Load[tlsAppConfig](WithDir(suite.dir))
+
+	suite.Require().Error(err)
+	problems := Problems((*tlsAppConfig)(nil), err)
+	suite.Require().Len(problems, 1)
+	suite.Assert().Equal("Upstream", problems[0].Field)
+}
+
+func (suite *TLSTestSuite) TestItDefaultsToTLS12() {
+	suite.Assert().Equal(uint16(tls.VersionTLS12), (&TLS{}).TLSConfig().MinVersion)
+}
+
+func TestTLSSuite(t *testing.T) {
+	suite.Run(t, new(TLSTestSuite))
+}
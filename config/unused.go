@@ -0,0 +1,126 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// UnusedKeysPolicy controls how CompositeConfig reports keys declared in env files that are not
+// read by any `env` tagged field of the config tree, which usually are typos like DB_HSOT.
+type UnusedKeysPolicy int
+
+const (
+	// IgnoreUnusedKeys does not check env file keys. This is the default.
+	IgnoreUnusedKeys UnusedKeysPolicy = iota
+	// WarnUnusedKeys logs a warning for every unused key.
+	WarnUnusedKeys
+	// FailOnUnusedKeys makes PopulateAndValidate return an *UnusedKeysError.
+	FailOnUnusedKeys
+)
+
+// EnvKeyReader is implemented by configs reading env variables in their Populate method, e.g. with
+// os.Getenv, rather than through `env` tags. The keys it returns are considered read by the unused
+// keys check, which otherwise reports them.
+type EnvKeyReader interface {
+	EnvKeys() []string
+}
+
+// UnusedKeysError lists the keys declared in env files that are not read by the config tree.
+type UnusedKeysError struct {
+	Keys []Origin
+}
+
+// Error lists the unused keys along with the file and line declaring them.
+func (e *UnusedKeysError) Error() string {
+	keys := make([]string, 0, len(e.Keys))
+	for _, key := range e.Keys {
+		keys = append(keys, key.Key+" ("+key.String()+")")
+	}
+
+	return "unused keys in env files: " + strings.Join(keys, ", ")
+}
+
+// checkUnusedKeys reports the declared env file keys that no `env` tag of the config tree reads,
+// according to the configured policy. Keys read with os.Getenv are only known when the config
+// reading them implements EnvKeyReader.
+func (c *CompositeConfig) checkUnusedKeys(compositeStruct interface{}, declaredKeys []Origin) error {
+	if c.unusedKeys == IgnoreUnusedKeys {
+		return nil
+	}
+
+	unused := c.findUnusedKeys(compositeStruct, declaredKeys)
+	if len(unused) == 0 {
+		return nil
+	}
+
+	if c.unusedKeys == FailOnUnusedKeys {
+		return &UnusedKeysError{Keys: unused}
+	}
+
+	for _, key := range unused {
+		c.logger.Warn("unused key in env file", "key", key.Key, "file", key.File, "line", key.Line)
+	}
+
+	return nil
+}
+
+// findUnusedKeys returns the declared keys that are not read by an `env` tagged field or listed by
+// an EnvKeyReader. The indexed keys of slices of structs are read by the fields of the populated
+// elements, so misspelled ones like UPSTREAM_0_HSOT are reported.
+func (c *CompositeConfig) findUnusedKeys(
+	compositeStruct interface{},
+	declaredKeys []Origin,
+) []Origin {
+	readKeys := make(map[string]bool)
+	visiting := make(map[uintptr]bool)
+	c.walkEnvFields(reflect.ValueOf(compositeStruct), "", "", visiting, func(_ string, key string) {
+		readKeys[key] = true
+	})
+	collectEnvKeyReaders(reflect.ValueOf(compositeStruct), make(map[uintptr]bool), readKeys)
+
+	var unused []Origin
+	for _, key := range declaredKeys {
+		if !readKeys[key.Key] {
+			unused = append(unused, key)
+		}
+	}
+
+	return unused
+}
+
+// collectEnvKeyReaders adds the keys listed by the EnvKeyReader structs of a config tree to the
+// read keys. The visited pointers are skipped, so pointer cycles are followed once.
+func collectEnvKeyReaders(val reflect.Value, visited map[uintptr]bool, readKeys map[string]bool) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		if val.Kind() == reflect.Ptr {
+			if visited[val.Pointer()] {
+				return
+			}
+			visited[val.Pointer()] = true
+		}
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return
+	}
+
+	reader, ok := val.Interface().(EnvKeyReader)
+	if !ok && val.CanAddr() {
+		reader, ok = val.Addr().Interface().(EnvKeyReader)
+	}
+	if ok {
+		for _, key := range reader.EnvKeys() {
+			readKeys[key] = true
+		}
+	}
+
+	for i := 0; i < val.NumField(); i++ {
+		if val.Type().Field(i).IsExported() {
+			collectEnvKeyReaders(val.Field(i), visited, readKeys)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type UnusedKeysTestSuite struct {
+	suite.Suite
+	dir     string
+	envFile string
+}
+
+type unusedKeysConfig struct {
+	Host string `env:"UNUSED_DB_HOST"`
+	Port string `env:"UNUSED_DB_PORT"`
+}
+
+func (suite *UnusedKeysTestSuite) SetupTest() {
+	unsetEnv(suite.T(), "UNUSED_DB_HOST", "UNUSED_DB_HSOT", "UNUSED_DB_PORT")
+
+	suite.dir = suite.T().TempDir()
+	suite.envFile = filepath.Join(suite.dir, ".env.test")
+	content := "UNUSED_DB_PORT=5432\n# typo\nUNUSED_DB_HSOT=db\n"
+	suite.Require().NoError(os.WriteFile(suite.envFile, []byte(content), 0644))
+}
+
+func (suite *UnusedKeysTestSuite) TestItFailsOnUnusedKeysWithFileAndLine() {
+	compositeConfig := NewCompositeConfig(nil, WithUnusedKeys(FailOnUnusedKeys))
+
+	err := compositeConfig.PopulateAndValidate(&unusedKeysConfig{}, "test", suite.dir)
+
+	var unusedErr *UnusedKeysError
+	suite.Require().True(errors.As(err, &unusedErr))
+	suite.Assert().Equal(
+		[]Origin{{Key: "UNUSED_DB_HSOT", Source: SourceFile, File: suite.envFile, Line: 3}},
+		unusedErr.Keys,
+	)
+	suite.Assert().Contains(err.Error(), "UNUSED_DB_HSOT ("+suite.envFile+":3)")
+}
+
+func (suite *UnusedKeysTestSuite) TestItWarnsAboutUnusedKeys() {
+	var logs bytes.Buffer
+	compositeConfig := NewCompositeConfig(
+		nil,
+		WithUnusedKeys(WarnUnusedKeys),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	)
+
+	err := compositeConfig.PopulateAndValidate(&unusedKeysConfig{}, "test", suite.dir)
+
+	suite.Assert().NoError(err)
+	suite.Assert().Contains(logs.String(), "key=UNUSED_DB_HSOT")
+	suite.Assert().Contains(logs.String(), "line=3")
+	suite.Assert().NotContains(logs.String(), "UNUSED_DB_PORT")
+}
+
+type unusedKeysGetenvConfig struct {
+	Host string
+	Port string `env:"UNUSED_DB_PORT"`
+}
+
+func (g *unusedKeysGetenvConfig) Populate() error {
+	g.Host = os.Getenv("UNUSED_DB_HSOT")
+	return nil
+}
+
+func (g *unusedKeysGetenvConfig) EnvKeys() []string {
+	return []string{"UNUSED_DB_HSOT"}
+}
+
+func (suite *UnusedKeysTestSuite) TestItDoesNotReportTheKeysOfEnvKeyReaders() {
+	compositeConfig := NewCompositeConfig(nil, WithUnusedKeys(FailOnUnusedKeys))
+	appConfig := &struct {
+		Database unusedKeysGetenvConfig
+	}{}
+
+	err := compositeConfig.PopulateAndValidate(appConfig, "test", suite.dir)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal("db", appConfig.Database.Host)
+}
+
+func (suite *UnusedKeysTestSuite) TestItFindsUnusedKeysOfSelfReferencingConfigs() {
+	type node struct {
+		Database unusedKeysConfig
+		Next     *node
+	}
+	root := &node{}
+	root.Next = root
+	declaredKeys := []Origin{{Key: "UNUSED_DB_PORT"}, {Key: "UNUSED_DB_HSOT"}}
+
+	unused := NewCompositeConfig(nil).findUnusedKeys(root, declaredKeys)
+
+	suite.Assert().Equal([]Origin{{Key: "UNUSED_DB_HSOT"}}, unused)
+}
+
+func (suite *UnusedKeysTestSuite) TestItIgnoresUnusedKeysByDefault() {
+	err := NewCompositeConfig(nil).PopulateAndValidate(&unusedKeysConfig{}, "test", suite.dir)
+
+	suite.Assert().NoError(err)
+}
+
+func TestUnusedKeysSuite(t *testing.T) {
+	suite.Run(t, new(UnusedKeysTestSuite))
+}
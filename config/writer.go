@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteOptions customizes WriteEnvFile.
+type WriteOptions struct {
+	// Existing is the content of an env file to edit. Its comments, blank lines and key order are
+	// preserved: declarations of keys present in the values map get the new value and the other ones
+	// are kept as they are.
+	Existing []byte
+}
+
+// WriteEnvFile writes values as env file content, quoting and escaping them so that loading the
+// content (e.g. with LoadEnvVars) returns the same values. Keys missing from opts.Existing are
+// appended in alphabetical order.
+func WriteEnvFile(w io.Writer, values map[string]string, opts WriteOptions) error {
+	for key := range values {
+		if !isValidEnvKey(key) {
+			return fmt.Errorf("invalid env key %q", key)
+		}
+	}
+
+	var builder strings.Builder
+	written, err := writeExistingEnvFile(&builder, opts.Existing, values)
+	if err != nil {
+		return fmt.Errorf("failed to parse existing env file: %w", err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !written[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		builder.WriteString(formatEnvDeclaration(dotenvEntry{Key: key}, values[key]))
+	}
+
+	_, err = io.WriteString(w, builder.String())
+	return err
+}
+
+// writeExistingEnvFile writes the lines of an existing env file, replacing the declarations of the
+// keys present in values, and returns the replaced keys.
+func writeExistingEnvFile(
+	builder *strings.Builder,
+	existing []byte,
+	values map[string]string,
+) (map[string]bool, error) {
+	written := make(map[string]bool)
+	if len(existing) == 0 {
+		return written, nil
+	}
+
+	entries, err := parseDotenv(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	declarations := make(map[int]dotenvEntry, len(entries))
+	for _, entry := range entries {
+		declarations[entry.Line] = entry
+	}
+
+	content := strings.TrimSuffix(strings.ReplaceAll(string(existing), "\r\n", "\n"), "\n")
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		entry, isDeclaration := declarations[i+1]
+		value, replaced := values[entry.Key]
+		if !isDeclaration || !replaced {
+			builder.WriteString(lines[i] + "\n")
+			continue
+		}
+
+		builder.WriteString(formatEnvDeclaration(entry, value))
+		written[entry.Key] = true
+		i = entry.EndLine - 1
+	}
+
+	return written, nil
+}
+
+// formatEnvDeclaration formats a declaration line, keeping the export prefix and inline comment of
+// the entry it replaces.
+func formatEnvDeclaration(entry dotenvEntry, value string) string {
+	declaration := entry.Key + "=" + quoteEnvValue(value)
+	if entry.Export {
+		declaration = "export " + declaration
+	}
+	if entry.Comment != "" {
+		declaration += " " + entry.Comment
+	}
+
+	return declaration + "\n"
+}
+
+// quoteEnvValue returns the value unquoted when it is safe, or double-quoted with the characters
+// meaningful to the parser and to interpolation escaped.
+func quoteEnvValue(value string) string {
+	if !strings.ContainsAny(value, " \t\r\n#\"'\\$") {
+		return value
+	}
+
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type WriterTestSuite struct {
+	suite.Suite
+}
+
+// loadWritten parses and resolves written env file content the way the Loader does.
+func (suite *WriterTestSuite) loadWritten(content []byte) map[string]string {
+	entries, err := parseDotenv(content)
+	suite.Require().NoError(err)
+
+	noEnv := func(string) (string, bool) { return "", false }
+	values, err := newEnvResolver([][]dotenvEntry{entries}, noEnv).resolveAll()
+	suite.Require().NoError(err)
+
+	return values
+}
+
+func (suite *WriterTestSuite) TestItWritesValuesThatRoundTrip() {
+	values := map[string]string{
+		"PLAIN":     "postgres://user@localhost:5432/db?sslmode=disable",
+		"EMPTY":     "",
+		"SPACES":    "  padded value ",
+		"DOLLAR":    "${NOT_A_REFERENCE} $HOME \\$",
+		"QUOTES":    `say "hi" it's`,
+		"MULTILINE": "-----BEGIN KEY-----\nabc\r\n\t-----END KEY-----",
+		"HASH":      "#not-a-comment",
+		"BACKSLASH": `C:\path\`,
+		"UNICODE":   "héllo wörld",
+	}
+	var output bytes.Buffer
+
+	err := WriteEnvFile(&output, values, WriteOptions{})
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(values, suite.loadWritten(output.Bytes()))
+	suite.Assert().Contains(output.String(), "EMPTY=\nHASH=\"#not-a-comment\"\n")
+	suite.Assert().Contains(output.String(), "PLAIN=postgres://user@localhost:5432/db?sslmode=disable\n")
+}
+
+func (suite *WriterTestSuite) TestItPreservesCommentsAndOrderWhenEditing() {
+	existing := "# Database\r\n" +
+		"export DB_HOST=localhost # local only\n" +
+		"DB_CERT=\"line1\n" +
+		"line2\"\n" +
+		"\n" +
+		"# Cache\n" +
+		"REDIS_HOST=redis\n"
+	var output bytes.Buffer
+
+	err := WriteEnvFile(
+		&output,
+		map[string]string{"DB_HOST": "db.internal", "DB_CERT": "cert", "APP_NAME": "my app"},
+		WriteOptions{Existing: []byte(existing)},
+	)
+
+	suite.Require().NoError(err)
+	suite.Assert().Equal(
+		"# Database\n"+
+			"export DB_HOST=db.internal # local only\n"+
+			"DB_CERT=cert\n"+
+			"\n"+
+			"# Cache\n"+
+			"REDIS_HOST=redis\n"+
+			"APP_NAME=\"my app\"\n",
+		output.String(),
+	)
+}
+
+func (suite *WriterTestSuite) TestItRejectsInvalidInput() {
+	testCases := []struct {
+		name    string
+		values  map[string]string
+		opts    WriteOptions
+		message string
+	}{
+		{"invalid key", map[string]string{"BAD KEY": "x"}, WriteOptions{}, `invalid env key "BAD KEY"`},
+		{
+			"invalid existing file",
+			map[string]string{"KEY": "x"},
+			WriteOptions{Existing: []byte("KEY=\"unterminated\n")},
+			"failed to parse existing env file",
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			err := WriteEnvFile(&bytes.Buffer{}, testCase.values, testCase.opts)
+
+			suite.Require().Error(err)
+			suite.Assert().Contains(err.Error(), testCase.message)
+		})
+	}
+}
+
+func TestWriterSuite(t *testing.T) {
+	suite.Run(t, new(WriterTestSuite))
+}
@@ -4,8 +4,8 @@ go 1.24.1
 
 require (
 	github.com/go-playground/validator/v10 v10.27.0
-	github.com/joho/godotenv v1.5.1
 	github.com/stretchr/testify v1.10.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -19,5 +19,4 @@ require (
 	golang.org/x/net v0.43.0 // indirect
 	golang.org/x/sys v0.35.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )